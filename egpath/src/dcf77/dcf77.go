@@ -40,15 +40,56 @@ type Date struct {
 	Summer bool
 }
 
+// Weekday returns t.Wday (1 means Monday, 7 means Sunday) converted to
+// time.Weekday. It returns -1 if the day of week was not decoded (t.Wday == 0).
+func (t Date) Weekday() time.Weekday {
+	if t.Wday <= 0 || t.Wday > 7 {
+		return -1
+	}
+	return time.Weekday(t.Wday % 7)
+}
+
 func (t Date) Format(f fmt.State, _ rune) {
 	zone := "CET"
 	if t.Summer {
 		zone = "CES"
 	}
+	wday := "???"
+	if wd := t.Weekday(); wd >= 0 {
+		wday = wd.String()[:3]
+	}
 	fmt.Fprintf(
 		f,
-		"%02d-%02d-%02d %02d:%02d:%02d %s",
-		t.Year, t.Month, t.Mday, t.Hour, t.Min, t.Sec, zone,
+		"%02d-%02d-%02d %s %02d:%02d:%02d %s",
+		t.Year, t.Month, t.Mday, wday, t.Hour, t.Min, t.Sec, zone,
+	)
+}
+
+//emgo:const
+var (
+	cet  = time.Zone{"CET", 1 * 3600}
+	cest = time.Zone{"CEST", 2 * 3600}
+
+	locCET  = time.Location{"CET", &cet, nil}
+	locCEST = time.Location{"CEST", &cest, nil}
+)
+
+// Time returns t as time.Time in the fixed CET (UTC+1) or CEST (UTC+2) zone,
+// selected by t.Summer. Two-digit t.Year is interpreted as 2000+t.Year.
+//
+// DCF77 transmits the zone that is valid for the encoded minute so there is no
+// ambiguity around the summer/winter transitions: 01:59 CET is followed by
+// 03:00 CEST and 02:59 CEST is followed by 02:00 CET. Both 02:xx CEST and
+// 02:xx CET are correctly converted to different instants because the zone
+// offset is never inferred from the local time.
+func (t Date) Time() time.Time {
+	loc := &locCET
+	if t.Summer {
+		loc = &locCEST
+	}
+	return time.Date(
+		2000+int(t.Year), time.Month(t.Month), int(t.Mday),
+		int(t.Hour), int(t.Min), int(t.Sec), 0, loc,
 	)
 }
 
@@ -61,24 +102,93 @@ type pulse struct {
 
 type Decoder struct {
 	// ISR fields.
-	pulse pulse
-	n     byte
+	pulse    pulse
+	n        byte
+	inverted bool
+	timerrs  int
+	dropped  int
 
 	// User fields.
-	date Date
+	date    Date
+	leap    bool
+	biterrs int
+	minutes int
 
 	// Common fields.
 	c chan pulse
 }
 
-// NewDecoder returns pointer to new ready to use DCF77 signal decoder.
-func NewDecoder() *Decoder {
+// NewDecoder returns pointer to new ready to use DCF77 signal decoder. Decoder
+// buffers up to buflen decoded pulses (buflen < 1 is treated as 1). If the
+// buffer is full, new pulses are dropped and counted (see Stats). Use buflen
+// greater than 1 if consumer must see every second but can be delayed.
+func NewDecoder(buflen int) *Decoder {
+	if buflen < 1 {
+		buflen = 1
+	}
 	d := new(Decoder)
 	d.pulse.sec = int8(ErrInit)
-	d.c = make(chan pulse, 1)
+	d.c = make(chan pulse, buflen)
+	return d
+}
+
+// NewDecoderPolarity works like NewDecoder but allows to specify the polarity
+// of the receiver output. Set inverted to true for receivers with active-low
+// output, where the rising edge marks the end of the carrier reduction pulse.
+func NewDecoderPolarity(inverted bool, buflen int) *Decoder {
+	d := NewDecoder(buflen)
+	d.inverted = inverted
 	return d
 }
 
+// Reset resets d to the initial state, the same as returned by NewDecoder,
+// without reallocating its internal buffer. It does not reset statistics (see
+// ResetStats). It can be used after long signal dropout (eg. the receiver was
+// re-tuned) to avoid waiting for the next sync pulse in the timing error
+// state. Reset must not run concurrently with Edge, so disable the edge
+// interrupt before calling it.
+func (d *Decoder) Reset() {
+	d.pulse = pulse{sec: int8(ErrInit)}
+	d.n = 0
+	d.date = Date{}
+	d.leap = false
+	for len(d.c) > 0 {
+		<-d.c
+	}
+}
+
+// Stats contains signal quality counters.
+type Stats struct {
+	TimingErrs int // Number of pulse timing errors.
+	BitsErrs   int // Number of minutes with invalid bits.
+	Minutes    int // Number of successfully decoded minutes.
+	Dropped    int // Number of pulses dropped because of full buffer.
+}
+
+// Stats returns signal quality counters accumulated since the decoder was
+// created or since last ResetStats call.
+func (d *Decoder) Stats() Stats {
+	return Stats{d.timerrs, d.biterrs, d.minutes, d.dropped}
+}
+
+// ResetStats resets signal quality counters. It can be used to measure
+// reception quality in specified time window.
+func (d *Decoder) ResetStats() {
+	d.timerrs = 0
+	d.biterrs = 0
+	d.minutes = 0
+	d.dropped = 0
+}
+
+// timingError switches d to ErrTiming state. Only transition to this state is
+// counted as timing error.
+func (d *Decoder) timingError() {
+	if Error(d.pulse.sec) != ErrTiming {
+		d.timerrs++
+	}
+	d.pulse.sec = int8(ErrTiming)
+}
+
 func checkRising(dt64 time.Duration) int {
 	if dt64 > 2050e6 {
 		return -1
@@ -98,6 +208,11 @@ func checkRising(dt64 time.Duration) int {
 func (d *Decoder) risingEdge(dt time.Duration) {
 	switch checkRising(dt) {
 	case 0: // Ordinary pulse.
+		if d.n >= 59 {
+			// Missing sync pulse (the leap second is the 59th).
+			d.timingError()
+			return
+		}
 		d.n++
 		if d.pulse.sec >= 0 {
 			d.pulse.sec = int8(d.n)
@@ -110,7 +225,7 @@ func (d *Decoder) risingEdge(dt time.Duration) {
 			d.pulse.sec = int8(ErrInit)
 		}
 	default:
-		d.pulse.sec = int8(ErrTiming)
+		d.timingError()
 	}
 }
 
@@ -136,7 +251,7 @@ func (d *Decoder) fallingEdge(dt time.Duration) {
 	}
 	bit := checkFalling(dt)
 	if bit < 0 {
-		d.pulse.sec = int8(ErrTiming)
+		d.timingError()
 	}
 	n := int(d.n) - 16
 	switch {
@@ -153,8 +268,13 @@ func (d *Decoder) fallingEdge(dt time.Duration) {
 }
 
 // Edge should be called by interrupt handler trigered by both (rising and
-// falling) edges of DCF77 signal pulses.
+// falling) edges of DCF77 signal pulses. The rising parameter describes the
+// edge of the receiver output. It is swapped internally if the decoder was
+// created for the inverted receiver (see NewDecoderPolarity).
 func (d *Decoder) Edge(t time.Time, rising bool) {
+	if d.inverted {
+		rising = !rising
+	}
 	dt := t.Sub(d.pulse.stamp)
 	lastsec := d.pulse.sec
 	if rising {
@@ -167,6 +287,7 @@ func (d *Decoder) Edge(t time.Time, rising bool) {
 		select {
 		case d.c <- d.pulse:
 		default:
+			d.dropped++
 		}
 	}
 }
@@ -174,6 +295,11 @@ func (d *Decoder) Edge(t time.Time, rising bool) {
 type Pulse struct {
 	Date
 	Stamp time.Time
+
+	// Leap is true if leap second was announced for the end of the current
+	// hour. In the last minute of such hour Sec can be 59 (the additional
+	// pulse, always zero) before the sync pulse of the next minute.
+	Leap bool
 }
 
 func (p *Pulse) Err() error {
@@ -183,8 +309,15 @@ func (p *Pulse) Err() error {
 	return nil
 }
 
+// checkParity reports whether u with parity bit pbit&1 has even parity.
 func checkParity(u, pbit uint32) bool {
-	return true
+	u ^= pbit & 1
+	u ^= u >> 16
+	u ^= u >> 8
+	u ^= u >> 4
+	u ^= u >> 2
+	u ^= u >> 1
+	return u&1 == 0
 }
 
 func decodeBCD(u uint32) (int8, bool) {
@@ -215,35 +348,84 @@ func (d *Decoder) decodeDate(l, h uint32) {
 	u = l>>(36-16) + h<<(32-36+16)
 	d.date.Mday, o = decodeBCD(u >> (36 - 36) & 0x3f)
 	ok = ok && o && uint(d.date.Mday)-1 < 31
-	d.date.Wday = int8(l >> (42 - 16))
+	d.date.Wday = int8(l >> (42 - 16) & 7)
 	ok = ok && d.date.Wday != 0
 	d.date.Month, o = decodeBCD(u >> (45 - 36) & 0x1f)
 	ok = ok && o && uint(d.date.Month)-1 < 12
 	d.date.Year, o = decodeBCD(u >> (50 - 36))
 	ok = ok && o && uint(d.date.Year) < 100
 	ok = ok && checkParity(u&0x3fffff, u>>22)
+	d.leap = ok && l&(1<<(19-16)) != 0
 	if ok {
 		d.date.Sec = 0
+		d.minutes++
 	} else {
 		d.date.Sec = int8(ErrBits)
+		d.biterrs++
 	}
 }
 
-// Pulse returns next decoded pulse. Decoder contains internal buffer for one
-// value, so if Pulse is called with period > 1 second, it should be called
-// twice to obtain most recent value.
+// DecodeMinute decodes complete minute of DCF77 bits, already sampled by
+// other means (bits[i] is the value of i-th second of minute). It performs the
+// same checks as Decoder and returns ErrBits if any of them fails. Sec field of
+// returned date is always zero.
+func DecodeMinute(bits [59]bool) (Date, error) {
+	var l, h uint32
+	for i, b := range bits[16:48] {
+		if b {
+			l |= 1 << uint(i)
+		}
+	}
+	for i, b := range bits[48:] {
+		if b {
+			h |= 1 << uint(i)
+		}
+	}
+	var d Decoder
+	d.decodeDate(l, h)
+	if d.date.Sec < 0 {
+		return Date{}, ErrBits
+	}
+	return d.date, nil
+}
+
+// update updates d.date using p. It returns false if p should be skipped.
+func (d *Decoder) update(p pulse) bool {
+	if p.sec == 0 {
+		d.decodeDate(p.l, uint32(p.h))
+		return true
+	}
+	if d.date.Sec >= 0 || p.sec < 0 {
+		d.date.Sec = p.sec
+		return true
+	}
+	return false
+}
+
+// Pulse returns next decoded pulse. Decoder contains internal buffer for
+// buflen values (see NewDecoder), so if Pulse is called with period > 1
+// second, it should be called repeatedly (eg. using TryPulse) to obtain most
+// recent value.
 func (d *Decoder) Pulse() Pulse {
-	var p pulse
 	for {
-		p = <-d.c
-		if p.sec == 0 {
-			d.decodeDate(p.l, uint32(p.h))
-			break
+		p := <-d.c
+		if d.update(p) {
+			return Pulse{d.date, p.stamp, d.leap}
 		}
-		if d.date.Sec >= 0 || p.sec < 0 {
-			d.date.Sec = p.sec
-			break
+	}
+}
+
+// TryPulse works like Pulse but does not block. It returns false if there is
+// no decoded pulse ready.
+func (d *Decoder) TryPulse() (Pulse, bool) {
+	for {
+		select {
+		case p := <-d.c:
+			if d.update(p) {
+				return Pulse{d.date, p.stamp, d.leap}, true
+			}
+		default:
+			return Pulse{}, false
 		}
 	}
-	return Pulse{d.date, p.stamp}
 }
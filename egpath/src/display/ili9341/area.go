@@ -13,6 +13,7 @@ type Area struct {
 	height uint16
 	color  uint16
 	swapWH bool
+	font   *Font
 }
 
 func (a *Area) P0() image.Point {
@@ -37,7 +38,7 @@ func (a *Area) Bounds() image.Rectangle {
 
 // SetColor sets the color used by drawing methods.
 func (a *Area) SetColorRGB(r, g, b byte) {
-	a.color = uint16(r)>>3<<11 | uint16(g)>>2<<5 | uint16(b)>>3
+	a.color = rgb565(r, g, b)
 }
 
 // SetColor sets the color used by drawing methods.
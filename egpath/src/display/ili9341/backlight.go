@@ -0,0 +1,7 @@
+package ili9341
+
+// Backlight represents a PWM output that drives the display backlight.
+type Backlight interface {
+	// SetLevel sets the PWM duty cycle to level/255.
+	SetLevel(level uint8)
+}
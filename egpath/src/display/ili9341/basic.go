@@ -39,9 +39,13 @@ func (a *Area) rawFillRect(x0, y0, x1, y1, wxh int) {
 	dci.Fill(uint16(a.color), wxh)
 }
 
-// FillRect draws a filled rectangle. 16-bit command.
+// FillRect draws a filled rectangle. It is clipped to the partial area if the
+// display is in partial mode (see Display.SetPartialArea). 16-bit command.
 func (a *Area) FillRect(r image.Rectangle) {
 	r = r.Canon().Intersect(a.Bounds())
+	if a.disp.ptl {
+		r = r.Intersect(a.disp.active().Sub(a.P0()))
+	}
 	if !r.Empty() {
 		a.rawFillRect(r.Min.X, r.Min.Y, r.Max.X-1, r.Max.Y-1, r.Dx()*r.Dy())
 	}
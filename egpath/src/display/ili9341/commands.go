@@ -3,17 +3,25 @@ package ili9341
 // These commands can be used to directly interract with display controller
 // using DCI.
 const (
-	NOP     = 0x00
-	SWRESET = 0x01
-	SPLIN   = 0x10
-	SLPOUT  = 0x11
-	DISPOFF = 0x28
-	DISPON  = 0x29
-	RAMWR   = 0x2C
-	MADCTL  = 0x36
-	PIXSET  = 0x3A
-	CASET   = 0x2A
-	PASET   = 0x2B
+	NOP      = 0x00
+	SWRESET  = 0x01
+	SPLIN    = 0x10
+	SLPOUT   = 0x11
+	PTLON    = 0x12
+	NORON    = 0x13
+	DISPOFF  = 0x28
+	DISPON   = 0x29
+	TEOFF    = 0x34
+	TEON     = 0x35
+	RAMWR    = 0x2C
+	RAMRD    = 0x2E
+	PTLAR    = 0x30
+	MADCTL   = 0x36
+	PIXSET   = 0x3A
+	CASET    = 0x2A
+	PASET    = 0x2B
+	VSCRDEF  = 0x33
+	VSCRSADD = 0x37
 )
 
 // Reset invokes Software Reset command (8-bit).
@@ -37,6 +45,13 @@ func (d *Display) DispOn() {
 	d.dci.Cmd(DISPON)
 }
 
+// NorOn invokes Normal Display Mode ON command (8-bit). It turns off the
+// partial mode set by SetPartialArea.
+func (d *Display) NorOn() {
+	d.dci.Cmd(NORON)
+	d.ptl = false
+}
+
 // MAD is a bitmask that describes memory access direction.
 type MAD byte
 
@@ -53,8 +68,36 @@ const (
 func (d *Display) MADCtl(mad MAD) {
 	d.dci.Cmd(MADCTL)
 	d.dci.WriteByte(byte(mad))
+	d.mad = mad
 	d.swapWH = mad&MV != 0
+}
+
+// Orientation describes display orientation.
+type Orientation byte
+
+const (
+	Rotate0   Orientation = iota // Portrait.
+	Rotate90                     // Landscape.
+	Rotate180                    // Portrait, upside down.
+	Rotate270                    // Landscape, upside down.
+)
 
+// SetOrientation sets display orientation using MADCtl (8-bit). It changes
+// only MX, MY, MV bits. Other bits (eg. BGR) are preserved from the last MADCtl
+// call. Bounds of Display and its Areas reflect the new orientation.
+func (d *Display) SetOrientation(o Orientation) {
+	mad := d.mad &^ (MX | MY | MV)
+	switch o & 3 {
+	case Rotate0:
+		mad |= MX
+	case Rotate90:
+		mad |= MV
+	case Rotate180:
+		mad |= MY
+	case Rotate270:
+		mad |= MX | MY | MV
+	}
+	d.MADCtl(mad)
 }
 
 // PixFmt describes pixel format.
@@ -70,3 +113,40 @@ func (d *Display) PixSet(pf PixFmt) {
 	d.dci.Cmd(PIXSET)
 	d.dci.WriteByte(byte(pf))
 }
+
+// SetScrollArea invokes Vertical Scrolling Definition command (16-bit). It
+// defines top fixed area, vertical scrolling area and bottom fixed area (in
+// lines). The sum of top, height and bottom should be equal to the number of
+// lines in frame memory (320). See SetPartialArea for the interaction with the
+// partial mode.
+func (d *Display) SetScrollArea(top, height, bottom int) {
+	d.dci.Cmd2(VSCRDEF)
+	d.dci.WriteWord(uint16(top))
+	d.dci.WriteWord(uint16(height))
+	d.dci.WriteWord(uint16(bottom))
+}
+
+// Scroll invokes Vertical Scrolling Start Address command (16-bit). It sets the
+// line in frame memory that is written as the first line of the vertical
+// scrolling area.
+func (d *Display) Scroll(line int) {
+	d.dci.Cmd2(VSCRSADD)
+	d.dci.WriteWord(uint16(line))
+}
+
+// SetPartialArea invokes Partial Area and Partial Mode ON commands (16-bit).
+// Only the lines from top to bottom (inclusive) of the panel are displayed. Top
+// must not be greater than bottom. Area.FillRect is clipped to the partial area
+// until NorOn is called. The lines are panel lines, so the partial area does not
+// move when the content is scrolled (see Scroll) but the clipping does not take
+// the scrolling into account: use both features together only if the scrolling
+// area and the partial area don't overlap.
+func (d *Display) SetPartialArea(top, bottom int) {
+	d.dci.Cmd2(PTLAR)
+	d.dci.WriteWord(uint16(top))
+	d.dci.WriteWord(uint16(bottom))
+	d.dci.Cmd2(PTLON)
+	d.ptlTop = uint16(top)
+	d.ptlBottom = uint16(bottom)
+	d.ptl = true
+}
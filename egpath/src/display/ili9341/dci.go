@@ -6,6 +6,7 @@ type DCI interface {
 
 	Cmd(b byte)       // Cmd invokes a command (8-bit word size).
 	WriteByte(b byte) // WriteByte pass one byte of data (8-bit word size).
+	Read(data []byte) // Read reads many bytes of data (8-bit word size).
 
 	SetWordSize(size int) // SetWordSize changes the data word to size bits.
 
@@ -6,9 +6,16 @@ import (
 
 type Display struct {
 	dci    DCI
+	bl     Backlight
+	te     TE
 	width  uint16
 	height uint16
+	mad    MAD
 	swapWH bool
+
+	ptl       bool
+	ptlTop    uint16
+	ptlBottom uint16
 }
 
 // MakeDisplay returns initialised Display value.
@@ -33,6 +40,43 @@ func (d *Display) DCI() DCI {
 	return d.dci
 }
 
+// SetBacklight sets the PWM output that controls the display backlight (see
+// SetBrightness). Nil bl means the display has no controllable backlight.
+func (d *Display) SetBacklight(bl Backlight) {
+	d.bl = bl
+}
+
+// SetBrightness sets the backlight brightness: 0 turns the backlight off, 255
+// means full brightness. It does nothing if the display has no Backlight (see
+// SetBacklight).
+func (d *Display) SetBrightness(level uint8) {
+	if d.bl != nil {
+		d.bl.SetLevel(level)
+	}
+}
+
+// SetTE sets the input connected to the display TE (tearing effect) output
+// and turns the TE output on using TEON command (8-bit). Nil te turns the TE
+// output off (TEOFF).
+func (d *Display) SetTE(te TE) {
+	d.te = te
+	if te == nil {
+		d.dci.Cmd(TEOFF)
+		return
+	}
+	d.dci.Cmd(TEON)
+	d.dci.WriteByte(0) // V-blanking information only.
+}
+
+// WaitVSync waits for the beginning of the vertical blanking period. Start
+// writing to the frame memory just after WaitVSync returns to avoid tearing. It
+// returns immediately if the display has no TE input (see SetTE).
+func (d *Display) WaitVSync() {
+	if d.te != nil {
+		d.te.Wait()
+	}
+}
+
 // Err returns and clears internal error variable.
 func (d *Display) Err(clear bool) error {
 	return d.dci.Err(clear)
@@ -46,6 +90,25 @@ func (d *Display) Bounds() image.Rectangle {
 	return image.Rectangle{Max: image.Pt(int(d.width), int(d.height))}
 }
 
+// active returns the part of the display bounds that is visible in the partial
+// mode.
+func (d *Display) active() image.Rectangle {
+	r := d.Bounds()
+	if !d.ptl {
+		return r
+	}
+	a, b := int(d.ptlTop), int(d.ptlBottom)+1
+	if d.mad&MY != 0 {
+		a, b = int(d.height)-b, int(d.height)-a
+	}
+	if d.swapWH {
+		r.Min.X, r.Max.X = a, b
+	} else {
+		r.Min.Y, r.Max.Y = a, b
+	}
+	return r.Intersect(d.Bounds())
+}
+
 // SetWordSize changes the data word size.
 func (d *Display) SetWordSize(size int) {
 	d.dci.SetWordSize(size)
@@ -26,7 +26,8 @@ func Run(lcd *ili9341.Display, n int, init bool) {
 		delay.Millisec(120)
 		lcd.DispOn()
 		lcd.PixSet(ili9341.PF16) // 16-bit pixel format.
-		lcd.MADCtl(ili9341.MY | ili9341.MX | ili9341.MV | ili9341.BGR)
+		lcd.MADCtl(ili9341.BGR)
+		lcd.SetOrientation(ili9341.Rotate270)
 	}
 	lcd.SetWordSize(16)
 
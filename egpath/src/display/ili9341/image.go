@@ -0,0 +1,140 @@
+package ili9341
+
+import (
+	"image"
+	"image/color"
+)
+
+func rgb565(r, g, b byte) uint16 {
+	return uint16(r)>>3<<11 | uint16(g)>>2<<5 | uint16(b)>>3
+}
+
+// DrawImage draws src in the area, so that src.Bounds().Min is placed at pt.
+// It supports any image.Image but *image.RGBA and *image.Paletted are
+// converted to the 16-bit 565 pixel format (see PF16) without calling At
+// method. Pixels are passed to the display in chunks using DCI.Write, so DCI
+// can use DMA for them. 16-bit command.
+func (a *Area) DrawImage(pt image.Point, src image.Image) {
+	sr := src.Bounds()
+	r := sr.Sub(sr.Min).Add(pt).Intersect(a.Bounds())
+	if r.Empty() {
+		return
+	}
+	sp := sr.Min.Add(r.Min.Sub(pt))
+	dci := a.disp.dci // Reduces code size.
+	dci.Cmd2(CASET)
+	dci.WriteWord(uint16(r.Min.X + int(a.x0)))
+	dci.WriteWord(uint16(r.Max.X - 1 + int(a.x0)))
+	dci.Cmd2(PASET)
+	dci.WriteWord(uint16(r.Min.Y + int(a.y0)))
+	dci.WriteWord(uint16(r.Max.Y - 1 + int(a.y0)))
+	dci.Cmd2(RAMWR)
+	var buf [64]uint16
+	n := 0
+	w, h := r.Dx(), r.Dy()
+	switch img := src.(type) {
+	case *image.RGBA:
+		for y := sp.Y; y < sp.Y+h; y++ {
+			i := img.PixOffset(sp.X, y)
+			for x := 0; x < w; x++ {
+				buf[n] = rgb565(img.Pix[i], img.Pix[i+1], img.Pix[i+2])
+				i += 4
+				if n++; n == len(buf) {
+					dci.Write(buf[:])
+					n = 0
+				}
+			}
+		}
+	case *image.Paletted:
+		var pal [256]uint16
+		for i, c := range img.Palette {
+			if i == len(pal) {
+				break
+			}
+			r, g, b, _ := c.RGBA()
+			pal[i] = uint16(r>>11<<11 | g>>10<<5 | b>>11)
+		}
+		for y := sp.Y; y < sp.Y+h; y++ {
+			i := img.PixOffset(sp.X, y)
+			for x := 0; x < w; x++ {
+				buf[n] = pal[img.Pix[i]]
+				i++
+				if n++; n == len(buf) {
+					dci.Write(buf[:])
+					n = 0
+				}
+			}
+		}
+	default:
+		for y := sp.Y; y < sp.Y+h; y++ {
+			for x := sp.X; x < sp.X+w; x++ {
+				r, g, b, _ := src.At(x, y).RGBA()
+				buf[n] = uint16(r>>11<<11 | g>>10<<5 | b>>11)
+				if n++; n == len(buf) {
+					dci.Write(buf[:])
+					n = 0
+				}
+			}
+		}
+	}
+	if n > 0 {
+		dci.Write(buf[:n])
+	}
+}
+
+// ReadRect reads the content of r from the display frame memory. It returns
+// image with bounds equal to r clipped to the area bounds. 8-bit command.
+//
+// The display sends 18-bit (666) pixels during read, regardless of the pixel
+// format set by PixSet. ILI9341 requires longer serial clock cycle for read
+// (150 ns) than for write (100 ns) so the DCI baudrate should be reduced to
+// about 6 MHz before reading (eg. using stm32/ilidci.DCI.SetBaudrate).
+func (a *Area) ReadRect(r image.Rectangle) *image.RGBA {
+	r = r.Canon().Intersect(a.Bounds())
+	img := image.NewRGBA(r)
+	if r.Empty() {
+		return img
+	}
+	dci := a.disp.dci // Reduces code size.
+	x0 := r.Min.X + int(a.x0)
+	x1 := r.Max.X - 1 + int(a.x0)
+	y0 := r.Min.Y + int(a.y0)
+	y1 := r.Max.Y - 1 + int(a.y0)
+	dci.Cmd(CASET)
+	dci.WriteByte(byte(x0 >> 8))
+	dci.WriteByte(byte(x0))
+	dci.WriteByte(byte(x1 >> 8))
+	dci.WriteByte(byte(x1))
+	dci.Cmd(PASET)
+	dci.WriteByte(byte(y0 >> 8))
+	dci.WriteByte(byte(y0))
+	dci.WriteByte(byte(y1 >> 8))
+	dci.WriteByte(byte(y1))
+	dci.Cmd(RAMRD)
+	var buf [3 * 16]byte
+	dci.Read(buf[:1]) // Dummy read.
+	pix := img.Pix
+	for len(pix) > 0 {
+		n := len(pix) / 4 * 3
+		if n > len(buf) {
+			n = len(buf)
+		}
+		dci.Read(buf[:n])
+		for i := 0; i < n; i += 3 {
+			r, g, b := buf[i], buf[i+1], buf[i+2]
+			pix[0] = r | r>>6
+			pix[1] = g | g>>6
+			pix[2] = b | b>>6
+			pix[3] = 0xff
+			pix = pix[4:]
+		}
+	}
+	return img
+}
+
+// At returns the color of the pixel at (x, y). It is a convenient but slow way
+// to read one pixel. See ReadRect for more information. 8-bit command.
+func (a *Area) At(x, y int) color.Color {
+	img := a.ReadRect(image.Rect(x, y, x+1, y+1))
+	return img.At(x, y)
+}
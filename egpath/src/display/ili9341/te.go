@@ -0,0 +1,7 @@
+package ili9341
+
+// TE represents an input connected to the display TE (tearing effect) output.
+type TE interface {
+	// Wait waits for the rising edge of the TE signal.
+	Wait()
+}
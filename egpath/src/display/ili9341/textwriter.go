@@ -5,9 +5,6 @@ import (
 	"image/color"
 )
 
-type Font struct {
-}
-
 // TextWriter allows to write a text on the display.
 type TextWriter struct {
 	area  *Area
@@ -29,7 +26,7 @@ func (w *TextWriter) Pos() image.Point {
 }
 
 func (w *TextWriter) SetColorRGB(r, g, b byte) {
-	w.color = uint16(r)>>3<<11 | uint16(g)>>2<<5 | uint16(b)>>3
+	w.color = rgb565(r, g, b)
 }
 
 func (w *TextWriter) SetColor(c color.Color) {
@@ -51,6 +51,10 @@ func (dci *DCI) WriteByte(b byte) {
 	dci.spi.WriteReadByte(b)
 }
 
+func (dci *DCI) Read(data []byte) {
+	dci.spi.WriteRead(nil, data)
+}
+
 func (dci *DCI) Cmd2(w uint16) {
 	dci.dc.Clear()
 	dci.spi.WriteReadWord16(w)
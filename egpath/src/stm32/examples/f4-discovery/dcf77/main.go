@@ -39,7 +39,7 @@ func init() {
 	rtos.IRQ(irq.EXTI1).Enable()
 }
 
-var d = dcf77.NewDecoder()
+var d = dcf77.NewDecoder(1)
 
 func edgeISR() {
 	t := time.Now()
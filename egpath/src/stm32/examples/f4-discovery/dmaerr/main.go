@@ -0,0 +1,97 @@
+// This example provokes DMA transfer errors to test that drivers report them
+// as dma.Error. It uses CCM RAM as DMA buffer: CCM is accessible by CPU but
+// not by DMA, so any DMA transfer from/to it sets the transfer error flag.
+//
+// Results are printed using SWO. USART2 Tx (PA2) need not be connected.
+package main
+
+import (
+	"delay"
+	"fmt"
+	"rtos"
+	"unsafe"
+
+	"stm32/hal/dma"
+	"stm32/hal/gpio"
+	"stm32/hal/irq"
+	"stm32/hal/system"
+	"stm32/hal/system/timer/systick"
+	"stm32/hal/usart"
+)
+
+var (
+	mtm *dma.Channel
+	tts *usart.Driver
+)
+
+func init() {
+	system.Setup168(8)
+	systick.Setup(2e6)
+
+	gpio.A.EnableClock(true)
+	port, tx := gpio.A, gpio.Pin2
+	port.Setup(tx, &gpio.Config{Mode: gpio.Alt})
+	port.SetAltFunc(tx, gpio.USART2)
+
+	d := dma.DMA2
+	d.EnableClock(true)
+	mtm = d.Channel(0, 0)
+
+	d = dma.DMA1
+	d.EnableClock(true)
+	tts = usart.NewDriver(usart.USART2, d.Channel(6, 4), nil, nil)
+	tts.Periph().EnableClock(true)
+	tts.Periph().SetBaudRate(115200)
+	tts.Periph().Enable()
+	tts.EnableTx()
+	rtos.IRQ(irq.USART2).Enable()
+	rtos.IRQ(irq.DMA1_Stream6).Enable()
+}
+
+const ccmBase = 0x10000000
+
+var ram [64]byte
+
+func ccm() []byte {
+	return (*[64]byte)(unsafe.Pointer(uintptr(ccmBase)))[:]
+}
+
+func check(name string, n int, err error) {
+	fmt.Printf("%-16s n=%2d err=%v: ", name, n, err)
+	if _, ok := err.(dma.Error); ok {
+		fmt.Println("OK")
+	} else {
+		fmt.Println("FAIL (want dma.Error)")
+	}
+}
+
+func main() {
+	delay.Millisec(250) // Wait for SWO (press reset if you see nothing).
+
+	n, err := mtm.MemCopy(ram[:], ccm())
+	check("MemCopy from CCM", n, err)
+
+	n, err = mtm.MemCopy(ccm(), ram[:])
+	check("MemCopy to CCM", n, err)
+
+	n, err = tts.Write(ccm())
+	check("USART Write", n, err)
+
+	n, err = tts.Write(ram[:])
+	fmt.Printf("%-16s n=%2d err=%v\n", "USART Write RAM", n, err)
+}
+
+func ttsISR() {
+	tts.ISR()
+}
+
+func ttsTxDMAISR() {
+	tts.TxDMAISR()
+}
+
+//emgo:const
+//c:__attribute__((section(".ISRs")))
+var ISRs = [...]func(){
+	irq.USART2:       ttsISR,
+	irq.DMA1_Stream6: ttsTxDMAISR,
+}
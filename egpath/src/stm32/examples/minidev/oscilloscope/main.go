@@ -113,7 +113,7 @@ func main() {
 	scr.FillRect(scr.Bounds())
 
 	adcd.P.SetSamplTime(1, adc.MaxSamplTime(1.5*2)) // 1.5 + 12.5 = 14
-	adcd.P.SetSequence(0)                           // PA0
+	adcd.SetSequence(0)                             // PA0
 	adcd.P.SetTrigSrc(adc.ADC12_TIM3_TRGO)
 	adcd.P.SetTrigEdge(adc.EdgeRising)
 	adcd.P.SetAlignLeft(true)
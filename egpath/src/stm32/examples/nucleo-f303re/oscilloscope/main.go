@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"delay"
 	"image"
 	"rtos"
+	"unsafe"
 
 	"display/ili9341"
 
@@ -16,6 +18,7 @@ import (
 	"stm32/hal/spi"
 	"stm32/hal/system"
 	"stm32/hal/system/timer/systick"
+	"stm32/hal/usart"
 
 	"stm32/hal/raw/opamp"
 	"stm32/hal/raw/rcc"
@@ -36,6 +39,7 @@ var (
 	lcd    *ili9341.Display
 	adcd   *adc.Driver
 	adct   *tim.TIM_Periph
+	con    *usart.Driver
 )
 
 func init() {
@@ -56,6 +60,7 @@ func init() {
 	gpio.C.EnableClock(true)
 	//spiport, sck, miso, mosi := gpio.C, gpio.Pin10, gpio.Pin11, gpio.Pin12
 	ilireset := gpio.C.Pin(13) // Max output: 2 MHz, 3 mA.
+	conport, contx, conrx := gpio.C, gpio.Pin4, gpio.Pin5
 
 	// DMA
 	dma1 := dma.DMA1
@@ -96,6 +101,8 @@ func init() {
 	adcd.P.EnableVoltage()
 	delay.Millisec(1)
 	adcd.P.SetClockMode(adc.HCLK1) // ADCclk = AHBclk = 72 Mhz
+	_, err := adcd.Calibrate(false)
+	checkErr(err)
 
 	rtos.IRQ(irq.ADC1_2).Enable()
 	rtos.IRQ(irq.DMA1_Channel1).Enable()
@@ -117,6 +124,308 @@ func init() {
 	adct = tim.TIM6
 	adct.CR2.Store(2 << tim.MMSn) // Update event as TRGO.
 	adct.CR1.Store(tim.CEN)
+
+	// Console USART (USART2 Tx pin PA2 is used as OPAMP1 output).
+
+	conport.Setup(contx, &gpio.Config{Mode: gpio.Alt})
+	conport.Setup(conrx, &gpio.Config{Mode: gpio.AltIn, Pull: gpio.PullUp})
+	conport.SetAltFunc(contx|conrx, gpio.USART1)
+	con = usart.NewDriver(
+		usart.USART1, dma1.Channel(4, 0), dma1.Channel(5, 0), make([]byte, 16),
+	)
+	con.Periph().EnableClock(true)
+	con.Periph().SetBaudRate(115200)
+	con.Periph().Enable()
+	con.EnableRx()
+	con.EnableTx()
+	con.SetReadDeadline(1) // Deadline in the past: Read does not block.
+
+	rtos.IRQ(irq.USART1).Enable()
+	rtos.IRQ(irq.DMA1_Channel4).Enable()
+	rtos.IRQ(irq.DMA1_Channel5).Enable()
+}
+
+type Slope byte
+
+const (
+	Rising Slope = iota
+	Falling
+)
+
+// Trigger describes when the trace starts. If Auto is true and the trigger
+// condition is not met the display free-runs. Otherwise it waits for the next
+// triggered trace.
+type Trigger struct {
+	Level byte
+	Slope Slope
+	Auto  bool
+}
+
+// Find returns the index of the first sample in buf that crosses t.Level in
+// direction specified by t.Slope or -1 if there is no such sample.
+func (t *Trigger) Find(buf []byte) int {
+	level := t.Level
+	if t.Slope == Rising {
+		for i, b := range buf[:len(buf)-1] {
+			if b < level && buf[i+1] >= level {
+				return i
+			}
+		}
+	} else {
+		for i, b := range buf[:len(buf)-1] {
+			if b >= level && buf[i+1] < level {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Cmd handles one character command received from console: '+', '-'
+// increase, decrease trigger level, 'r', 'f' select rising, falling slope and
+// 'a' toggles auto mode.
+func (t *Trigger) Cmd(c byte) {
+	switch c {
+	case '+':
+		if t.Level < 255-8 {
+			t.Level += 8
+		}
+	case '-':
+		if t.Level > 8 {
+			t.Level -= 8
+		}
+	case 'r':
+		t.Slope = Rising
+	case 'f':
+		t.Slope = Falling
+	case 'a':
+		t.Auto = !t.Auto
+	default:
+		con.WriteString("?\r\n")
+		return
+	}
+	t.print()
+}
+
+func (t *Trigger) print() {
+	con.WriteString("level=")
+	printUint(uint32(t.Level))
+	if t.Slope == Rising {
+		con.WriteString(" rising")
+	} else {
+		con.WriteString(" falling")
+	}
+	if t.Auto {
+		con.WriteString(" auto")
+	}
+	con.WriteString("\r\n")
+}
+
+// SamplesPerDiv is the number of samples (pixels) in one horizontal division.
+const SamplesPerDiv = 40
+
+// tbSteps contains the available timebases in µs/div.
+var tbSteps = [...]uint32{
+	10, 20, 50, 100, 200, 500,
+	1e3, 2e3, 5e3, 10e3, 20e3, 50e3, 100e3,
+}
+
+// Timebase describes the horizontal scale of the trace.
+//
+// ADC timer (TIM6) is clocked from 2 * APB1clk = 72 MHz, the same as ADC
+// (ADCclk = AHBclk = 72 MHz). In 8-bit resolution ADC needs 1.5 + 8.5 = 10
+// ADCclk cycles to convert one sample, so the sample period cannot be shorter
+// than 10 timer ticks (7.2 MHz). For SamplesPerDiv = 40 this gives the fastest
+// timebase equal to 10 µs/div (18 ticks, 4 MHz). The slowest one is limited by
+// the acquisition time of the whole buffer (four screens): for 100 ms/div it
+// takes 3.2 s, during which the console commands are not handled.
+type Timebase struct {
+	step int // Index in tbSteps.
+}
+
+// Cmd handles one character command received from console: '>', '<' select
+// the slower, faster timebase.
+func (tb *Timebase) Cmd(c byte) {
+	switch c {
+	case '>':
+		if tb.step < len(tbSteps)-1 {
+			tb.step++
+		}
+	case '<':
+		if tb.step > 0 {
+			tb.step--
+		}
+	}
+	tb.Setup()
+	tb.print()
+}
+
+// Setup recomputes PSC and ARR of the ADC timer. It must be called between
+// acquisitions: adc.Driver.Read stops ADC before return so the update event
+// generated to reload the prescaler does not start any conversion.
+func (tb *Timebase) Setup() {
+	ticks := tbSteps[tb.step] * 72 / SamplesPerDiv // Sample period.
+	div1 := uint32(1)
+	for ticks/div1 > 0x10000 || ticks%div1 != 0 {
+		div1++
+	}
+	adct.PSC.Store(tim.PSC(div1 - 1))
+	adct.ARR.Store(tim.ARR(ticks/div1 - 1))
+	adct.EGR.Store(tim.UG)
+}
+
+func (tb *Timebase) print() {
+	us := tbSteps[tb.step]
+	if us < 1e3 {
+		printUint(us)
+		con.WriteString(" us/div\r\n")
+	} else {
+		printUint(us / 1e3)
+		con.WriteString(" ms/div\r\n")
+	}
+}
+
+func printUint(u uint32) {
+	var buf [10]byte
+	con.Write(appendUint(buf[:0], u))
+}
+
+func appendUint(b []byte, u uint32) []byte {
+	var buf [10]byte
+	n := len(buf)
+	for {
+		n--
+		buf[n] = byte('0' + u%10)
+		if u < 10 {
+			break
+		}
+		u /= 10
+	}
+	return append(b, buf[n:]...)
+}
+
+func appendStr(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		b = append(b, s[i])
+	}
+	return b
+}
+
+// Measure contains signal parameters computed from the captured samples.
+type Measure struct {
+	Min, Max byte
+	Period   int // Average period in samples or 0 if unknown.
+}
+
+// Update computes min and max of the displayed samples and the signal period
+// from the spacing of the trigger crossings in the whole buf.
+func (m *Measure) Update(buf, disp []byte, trig *Trigger) {
+	min, max := byte(255), byte(0)
+	for _, b := range disp {
+		if b < min {
+			min = b
+		}
+		if b > max {
+			max = b
+		}
+	}
+	m.Min, m.Max = min, max
+	first, last, n := -1, 0, 0
+	for i := 0; i < len(buf)-1; i++ {
+		k := trig.Find(buf[i:])
+		if k < 0 {
+			break
+		}
+		i += k
+		if first < 0 {
+			first = i
+		}
+		last = i
+		n++
+	}
+	m.Period = 0
+	if n > 1 {
+		m.Period = (last - first) / (n - 1)
+	}
+}
+
+// millivolts converts the sample value to mV (Vref = 3.3 V).
+func millivolts(v byte) uint32 {
+	return uint32(v) * 3300 >> 8
+}
+
+// Frequency returns the signal frequency in Hz for the timebase tb or 0 if the
+// period is unknown.
+func (m *Measure) Frequency(tb *Timebase) uint32 {
+	if m.Period == 0 {
+		return 0
+	}
+	sr := 1e6 * SamplesPerDiv / uint64(tbSteps[tb.step]) // Sample rate.
+	return uint32(sr / uint64(m.Period))
+}
+
+const (
+	ovlLines = 4
+	ovlChars = 16
+)
+
+type ovlLine struct {
+	buf [ovlChars]byte
+	n   int
+}
+
+// Overlay displays text lines in the top left corner of the screen. The line
+// is redrawn only if its content has changed, so the overlay does not flicker.
+// The trace is not drawn in the overlay bounds.
+type Overlay struct {
+	area  *ili9341.Area
+	lines [ovlLines]ovlLine
+}
+
+// Bounds returns the part of the screen occupied by the overlay.
+func (o *Overlay) Bounds() image.Rectangle {
+	f := &ili9341.Font5x8
+	w := ovlChars*(int(f.Width)+1) + 2
+	h := ovlLines*(int(f.Height)+1) + 2
+	return image.Rect(0, 0, w, h)
+}
+
+// SetLine sets the content of the i-th line to s.
+func (o *Overlay) SetLine(i int, s []byte) {
+	l := &o.lines[i]
+	if len(s) > len(l.buf) {
+		s = s[:len(l.buf)]
+	}
+	if bytes.Equal(l.buf[:l.n], s) {
+		return
+	}
+	l.n = copy(l.buf[:], s)
+	f := &ili9341.Font5x8
+	y := 2 + i*(int(f.Height)+1)
+	a := o.area
+	a.SetColorRGB(0, 0, 0)
+	a.FillRect(image.Rect(0, y, o.Bounds().Max.X, y+int(f.Height)))
+	a.SetColorRGB(255, 255, 0)
+	b := l.buf[:l.n]
+	a.DrawString(image.Pt(2, y), *(*string)(unsafe.Pointer(&b)))
+}
+
+// SetValue sets the content of the i-th line to label followed by v and unit.
+func (o *Overlay) SetValue(i int, label string, v uint32, unit string) {
+	var buf [ovlChars]byte
+	b := appendStr(buf[:0], label)
+	b = appendUint(b, v)
+	b = appendStr(b, unit)
+	o.SetLine(i, b)
+}
+
+// Show displays m on o. Unknown frequency is displayed as 0 Hz.
+func (o *Overlay) Show(m *Measure, tb *Timebase) {
+	min, max := millivolts(m.Min), millivolts(m.Max)
+	o.SetValue(0, "min ", min, " mV")
+	o.SetValue(1, "max ", max, " mV")
+	o.SetValue(2, "p-p ", max-min, " mV")
+	o.SetValue(3, "f   ", m.Frequency(tb), " Hz")
 }
 
 func main() {
@@ -139,36 +448,49 @@ func main() {
 	adcd.P.SetTrigSrc(adc.ADC12_TIM6_TRGO)
 	adcd.P.SetTrigEdge(adc.EdgeRising)
 
-	adcd.Enable(true)
+	adcd.Enable(false)
 
-	div1, div2 := 2, 5 // ADC SR = 72 MHz / (div1 * div2)
-	adct.PSC.Store(tim.PSC(div1 - 1))
-	adct.ARR.Store(tim.ARR(div2 - 1))
-	adct.EGR.Store(tim.UG)
+	tb := Timebase{} // 10 µs/div.
+	tb.Setup()
+	tb.print()
 
 	wh := scr.Bounds().Max
 	scale := func(y byte) int { return wh.Y - 8 - int(y)*7/8 }
 	buf := make([]byte, wh.X*4)
-	const trig = 128
+	trig := Trigger{Level: 128, Slope: Rising, Auto: true}
+	trig.print()
+	ovl := Overlay{area: &scr}
+	ob := ovl.Bounds()
+	var meas Measure
+	var cmd [8]byte
 	for {
+		n, _ := con.Read(cmd[:]) // Ignore ErrTimeout (no data).
+		for _, c := range cmd[:n] {
+			switch c {
+			case '<', '>':
+				tb.Cmd(c)
+			default:
+				trig.Cmd(c)
+			}
+		}
+
 		_, err := adcd.Read(buf)
 		checkErr(err)
 
-		offset := -1
-		for i, b := range buf[:wh.X*3] {
-			if b < trig {
-				if buf[i+1] >= trig {
-					offset = i
-					break
-				}
-			}
-		}
+		offset := trig.Find(buf[:wh.X*3+1])
 		if offset < 0 {
-			offset = 0
+			if !trig.Auto {
+				continue // Wait for trigger.
+			}
+			offset = 0 // Free-run.
 		}
 		for x := 0; x < wh.X; x++ {
+			top := 0
+			if x < ob.Max.X {
+				top = ob.Max.Y // Do not draw in the overlay bounds.
+			}
 			scr.SetColorRGB(0, 0, 0)
-			scr.FillRect(image.Rect(x, 0, x+1, wh.Y))
+			scr.FillRect(image.Rect(x, top, x+1, wh.Y))
 			scr.SetColorRGB(255, 255, 255)
 			y0 := scale(buf[offset+x])
 			y1 := scale(buf[offset+x+1])
@@ -176,8 +498,15 @@ func main() {
 				y0, y1 = y1, y0
 			}
 			y1++
-			scr.FillRect(image.Rectangle{image.Pt(x, y0), image.Pt(x+1, y1)})
+			if y0 < top {
+				y0 = top
+			}
+			if y0 < y1 {
+				scr.FillRect(image.Rect(x, y0, x+1, y1))
+			}
 		}
+		meas.Update(buf, buf[offset:offset+wh.X+1], &trig)
+		ovl.Show(&meas, &tb)
 	}
 }
 
@@ -201,6 +530,18 @@ func adcDMAISR() {
 	adcd.DMAISR()
 }
 
+func conISR() {
+	con.ISR()
+}
+
+func conRxDMAISR() {
+	con.RxDMAISR()
+}
+
+func conTxDMAISR() {
+	con.TxDMAISR()
+}
+
 //emgo:const
 //c:__attribute__((section(".ISRs")))
 var ISRs = [...]func(){
@@ -210,4 +551,8 @@ var ISRs = [...]func(){
 
 	irq.ADC1_2:        adcISR,
 	irq.DMA1_Channel1: adcDMAISR,
+
+	irq.USART1:        conISR,
+	irq.DMA1_Channel4: conTxDMAISR,
+	irq.DMA1_Channel5: conRxDMAISR,
 }
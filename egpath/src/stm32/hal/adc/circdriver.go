@@ -151,6 +151,14 @@ func (d *CircDriver) HandleChan() <-chan int32 {
 	return d.hc
 }
 
+// Pos returns the offset (in bytes) of the next byte of the internal buffer
+// that will be written by DMA. It can be used to read the most recent samples
+// without waiting for the half-buffer handle.
+func (d *CircDriver) Pos() int {
+	_, ws := d.ch.WordSize()
+	return len(d.buf)*2 - d.ch.Len()*int(ws)
+}
+
 func (d *CircDriver) Words16(bh int32) []uint16 {
 	begin := int(bh)
 	end := begin + len(d.buf)/2
@@ -166,6 +174,8 @@ func (d *CircDriver) Bytes(bh int32) []byte {
 	return (*(*[]byte)(unsafe.Pointer(&sli)))[begin:end]
 }
 
+// Err returns and clears the error that occurred during conversion: Error (ADC
+// error), dma.Error (DMA transfer error) or DriverError (overrun).
 func (d *CircDriver) Err() error {
 	if atomic.LoadUint32(&d.err) == 0 {
 		return nil
@@ -1,3 +1,10 @@
 // Package adc provides interface to STM32 Analog to Digital Converter
 // peripheral. Additionaly it provides two drivers for easier use of ADC.
+//
+// Supported ADCs (STM32F1, STM32F3) have no hardware oversampler (there is no
+// CFGR2.OVSR/OVSS like in STM32L0/L4) so there is no SetOversampling method.
+// Averaging can be done in software: read ratio samples using Read16 and sum
+// them. The sum of 2^k samples of n-bit resolution (see SetResolution) needs
+// n+k bits, so shift it right by k to obtain the average or by less than k to
+// obtain the result with better effective resolution.
 package adc
@@ -14,12 +14,15 @@ type DriverError byte
 
 const (
 	ErrDrvOverrun DriverError = 1
+	ErrDrvTimeout DriverError = 2
 )
 
 func (e DriverError) Error() string {
 	switch e {
 	case ErrDrvOverrun:
 		return "drv.overrun"
+	case ErrDrvTimeout:
+		return "drv.timeout"
 	}
 	return ""
 }
@@ -29,8 +32,10 @@ type Driver struct {
 	DMA *dma.Channel
 
 	done    rtos.EventFlag
+	awd     rtos.EventFlag
 	waitfor uint32
 	offset  byte
+	seqlen  byte
 }
 
 // NewDriver provides convenient way to create heap allocated Driver.
@@ -42,17 +47,26 @@ func NewDriver(p *Periph, ch *dma.Channel) *Driver {
 }
 
 func (d *Driver) ISR() {
+	p := d.P
+	ev, err := p.Status()
+	if ev&awd != 0 {
+		// The status bit is set regardless of the interrupt enable bit, so
+		// only the enabled interrupt means WaitWatchdog is waiting.
+		if ie, _ := p.IRQEnabled(); ie&awd != 0 {
+			p.DisableIRQ(awd, 0)
+			d.awd.Signal(1)
+		}
+	}
 	waitfor := d.waitfor
 	if waitfor == 0 {
 		// Other ADC (shared IRQ).
 		return
 	}
-	p := d.P
-	if ev, err := p.Status(); (uint32(ev)<<16|uint32(err))&waitfor == 0 {
+	if (uint32(ev)<<16|uint32(err))&waitfor == 0 {
 		// Other ADC (shared IRQ).
 		return
 	}
-	p.DisableIRQ(EvAll, ErrAll)
+	p.DisableIRQ(Event(waitfor>>16), Error(waitfor))
 	d.waitfor = 0
 	d.done.Signal(1)
 }
@@ -62,6 +76,28 @@ func (d *Driver) Enable(calibrate bool) {
 	d.enable(calibrate)
 }
 
+const calTimeout = 10e6 // 10 ms
+
+// Calibrate runs ADC calibration for single-ended (diff == false) or
+// differential (diff == true) inputs, waits for its end and returns the
+// calibration factor. It returns ErrDrvTimeout if the calibration does not end
+// in 10 ms (eg. ADC clock is not enabled). STM32F3 ADC must be disabled,
+// STM32F1 ADC must be enabled before calibration. STM32F1 supports only
+// single-ended calibration and does not provide the calibration factor
+// (Calibrate returns 0).
+func (d *Driver) Calibrate(diff bool) (int, error) {
+	p := d.P
+	p.startCalibration(diff)
+	deadline := rtos.Nanosec() + calTimeout
+	for p.calibrating() {
+		if rtos.Nanosec() >= deadline {
+			return 0, ErrDrvTimeout
+		}
+		rtos.SchedYield()
+	}
+	return p.calibFactor(diff), nil
+}
+
 func (d *Driver) DMAISR() {
 	d.DMA.DisableIRQ(dma.EvAll, dma.ErrAll)
 	d.done.Signal(1)
@@ -82,6 +118,12 @@ func (d *Driver) readDMA(maddr unsafe.Pointer, n int, wsize uintptr) (int, error
 	if n > 0xffff {
 		n = 0xffff
 	}
+	if d.seqlen > 1 {
+		n -= n % int(d.seqlen)
+		if n == 0 {
+			return 0, nil
+		}
+	}
 	p, ch := d.P, d.DMA
 	paddr := p.raw.DR.U32.Addr()
 	if wsize == 1 {
@@ -101,14 +143,56 @@ func (d *Driver) readDMA(maddr unsafe.Pointer, n int, wsize uintptr) (int, error
 		ch.DisableIRQ(dma.EvAll, dma.ErrAll)
 		_, err = p.Status()
 	default:
-		p.DisableIRQ(EvAll, ErrAll)
-		if _, e := ch.Status(); e&^dma.ErrFIFO != 0 {
-			err = e
-		}
+		p.DisableIRQ(0, ErrAll)
+		err = ch.Err()
 	}
 	return n - ch.Len(), err
 }
 
+// SetSequence sets the regular sequence of channels (see Periph.SetSequence)
+// converted in response to one trigger. Read and Read16 store samples
+// interleaved: buf[i] contains sample of ch[i%len(ch)]. Every DMA element (byte
+// or 16-bit word) contains one sample, so the number of triggers needed to fill
+// buf is len(buf)/len(ch). Read and Read16 read only complete sequences
+// (len(buf) is rounded down to the multiple of len(ch)).
+func (d *Driver) SetSequence(ch ...int) {
+	d.P.SetSequence(ch...)
+	d.seqlen = byte(len(ch))
+}
+
+// SetWatchdog enables the analog watchdog (see Periph.SetWatchdog). Use
+// WaitWatchdog to wait for the watchdog event.
+func (d *Driver) SetWatchdog(low, high uint16, ch int) {
+	d.P.SetWatchdog(low, high, ch)
+}
+
+// WaitWatchdog waits for the analog watchdog event until deadline (see
+// rtos.EventFlag.Wait). It returns false if deadline has been reached. The
+// event that occurred before WaitWatchdog was called is ignored.
+func (d *Driver) WaitWatchdog(deadline int64) bool {
+	p := d.P
+	p.Clear(awd, 0)
+	d.awd.Reset(0)
+	fence.W() // To order writes to normal and I/O memory.
+	p.EnableIRQ(awd, 0)
+	ok := d.awd.Wait(1, deadline)
+	p.DisableIRQ(awd, 0)
+	return ok
+}
+
+// EnableTempSensor enables internal temperature sensor connected to
+// TempSensorCh channel. Use Temp to convert the result of conversion to °C.
+// The sensor requires long sampling time (see datasheet for Ts_temp).
+func (d *Driver) EnableTempSensor() {
+	d.P.enableTempSensor()
+}
+
+// EnableVref enables internal reference voltage connected to VrefCh channel.
+// Use Vdda to convert the result of conversion to analog supply voltage.
+func (d *Driver) EnableVref() {
+	d.P.enableVref()
+}
+
 // SetReadMSB sets most significant byte of 16-bit ADC data register to be read
 // by Read and ReadByte methods.
 func (d *Driver) SetReadMSB(rdmsb bool) {
@@ -56,12 +56,16 @@ func (e *Error) error() string {
 	return ""
 }
 
-func (p *Periph) calibrate() {
-	raw := &p.raw
-	raw.CAL().Set()
-	for raw.CAL().Load() != 0 {
-		rtos.SchedYield()
-	}
+func (p *Periph) startCalibration(_ bool) {
+	p.raw.CAL().Set()
+}
+
+func (p *Periph) calibrating() bool {
+	return p.raw.CAL().Load() != 0
+}
+
+func (p *Periph) calibFactor(_ bool) int {
+	return 0
 }
 
 func (p *Periph) enable() {
@@ -154,6 +158,23 @@ func (p *Periph) setTrigEdge(edge TrigEdge) {
 	p.raw.EXTTRIG().Store(adc.CR2(edge) << adc.EXTTRIGn)
 }
 
+const awd = Watchdog
+
+func (p *Periph) setWatchdog(low, high uint16, ch int) {
+	checkCh(ch)
+	raw := &p.raw
+	raw.LTR.Store(adc.LTR(low) & adc.LT)
+	raw.HTR.Store(adc.HTR(high) & adc.HT)
+	raw.CR1.StoreBits(
+		adc.AWDCH|adc.AWDSGL|adc.AWDEN,
+		adc.CR1(ch)<<adc.AWDCHn|adc.AWDSGL|adc.AWDEN,
+	)
+}
+
+func (p *Periph) disableWatchdog() {
+	p.raw.AWDEN().Clear()
+}
+
 func (p *Periph) status() (Event, Error) {
 	return Event(p.raw.SR.Load()), 0
 }
@@ -169,6 +190,14 @@ func (p *Periph) enableIRQ(ev Event, _ Error) {
 	p.raw.CR1.SetBits(adc.CR1(cr1))
 }
 
+func (p *Periph) irqEnabled() (Event, Error) {
+	cr1 := Event(p.raw.CR1.Load())
+	ev := cr1>>(adc.EOCIEn-adc.EOCn)&ConvEnd |
+		cr1>>(adc.JEOCIEn-adc.JEOCn)&InjConvEnd |
+		cr1>>(adc.AWDIEn-adc.AWDn)&Watchdog
+	return ev, 0
+}
+
 func (p *Periph) disableIRQ(ev Event, _ Error) {
 	cr1 := ev&ConvEnd<<(adc.EOCIEn-adc.EOCn) |
 		ev&InjConvEnd<<(adc.JEOCIEn-adc.JEOCn) |
@@ -5,7 +5,6 @@ package adc
 import (
 	"bits"
 	"delay"
-	"rtos"
 	"unsafe"
 
 	"stm32/hal/raw/adc"
@@ -160,12 +159,21 @@ func (p *Periph) DisableVoltage() {
 	raw.CR.Store(2 << adc.ADVREGENn)
 }
 
-func (p *Periph) calibrate() {
-	raw := &p.raw
-	raw.CR.Store(adc.ADCAL | advregen)
-	for raw.ADCAL().Load() != 0 {
-		rtos.SchedYield()
+func (p *Periph) startCalibration(diff bool) {
+	p.raw.CR.Store(
+		adc.ADCAL | adc.CR(bits.One(diff)<<adc.ADCALDIFn) | advregen,
+	)
+}
+
+func (p *Periph) calibrating() bool {
+	return p.raw.ADCAL().Load() != 0
+}
+
+func (p *Periph) calibFactor(diff bool) int {
+	if diff {
+		return int(p.raw.CALFACT_D().Load() >> adc.CALFACT_Dn)
 	}
+	return int(p.raw.CALFACT_S().Load())
 }
 
 func (p *Periph) enable() {
@@ -279,6 +287,22 @@ func (p *Periph) setTrigEdge(edge TrigEdge) {
 	p.raw.EXTEN().Store(adc.CFGR(edge) << adc.EXTENn)
 }
 
+const awd = Watchdog1
+
+func (p *Periph) setWatchdog(low, high uint16, ch int) {
+	checkCh(ch)
+	raw := &p.raw
+	raw.TR1.Store(adc.TR1(low)&adc.LT1 | adc.TR1(high)<<adc.HT1n&adc.HT1)
+	raw.CFGR.StoreBits(
+		adc.AWD1CH|adc.AWD1SGL|adc.AWD1EN,
+		adc.CFGR(ch)<<adc.AWD1CHn|adc.AWD1SGL|adc.AWD1EN,
+	)
+}
+
+func (p *Periph) disableWatchdog() {
+	p.raw.AWD1EN().Clear()
+}
+
 func (p *Periph) status() (Event, Error) {
 	v := p.raw.ISR.Load()
 	return Event(v) & EvAll, Error(v) & ErrAll
@@ -1,6 +1,7 @@
 package adc
 
 import (
+	"rtos"
 	"unsafe"
 
 	"stm32/hal/internal"
@@ -37,9 +38,13 @@ func (p *Periph) DisableClock() {
 	p.disableClock()
 }
 
-// Calibrate calibrates p.
+// Calibrate calibrates p for single-ended inputs and waits for the end of
+// calibration.
 func (p *Periph) Calibrate() {
-	p.calibrate()
+	p.startCalibration(false)
+	for p.calibrating() {
+		rtos.SchedYield()
+	}
 }
 
 // Enable enables p.
@@ -106,6 +111,21 @@ func (p *Periph) SetTrigEdge(edge TrigEdge) {
 	p.setTrigEdge(edge)
 }
 
+// SetWatchdog enables the analog watchdog that monitors regular channel ch.
+// The watchdog event is generated when the converted value of ch is lower
+// than low or higher than high. Thresholds are 12-bit values compared with 12
+// most significant bits of the conversion result, independent of resolution
+// and data alignment. STM32F3 does not allow to change the watchdog
+// configuration when the conversion is started.
+func (p *Periph) SetWatchdog(low, high uint16, ch int) {
+	p.setWatchdog(low, high, ch)
+}
+
+// DisableWatchdog disables the analog watchdog.
+func (p *Periph) DisableWatchdog() {
+	p.disableWatchdog()
+}
+
 func (p *Periph) SetAlignLeft(alignLeft bool) {
 	if alignLeft {
 		p.raw.ALIGN().Set()
@@ -138,11 +158,9 @@ func (p *Periph) EnableIRQ(ev Event, err Error) {
 	p.enableIRQ(ev, err)
 }
 
-/*
 func (p *Periph) IRQEnabled() (Event, Error) {
 	return p.irqEnabled()
 }
-*/
 
 func (p *Periph) DisableIRQ(ev Event, err Error) {
 	p.disableIRQ(ev, err)
@@ -0,0 +1,36 @@
+// +build f10x_ld f10x_ld_vl f10x_md f10x_md_vl f10x_hd f10x_hd_vl f10x_xl f10x_cl
+
+package adc
+
+// Internal channels (ADC1 only).
+const (
+	TempSensorCh = 16 // Temperature sensor.
+	VrefCh       = 17 // Internal reference voltage.
+)
+
+// STM32F1 uses one bit to enable both internal channels.
+
+func (p *Periph) enableTempSensor() {
+	p.raw.TSVREFE().Set()
+}
+
+func (p *Periph) enableVref() {
+	p.raw.TSVREFE().Set()
+}
+
+// Vdda returns analog supply voltage (V) calculated from the 12-bit right
+// aligned result of conversion of VrefCh. STM32F1 has no factory calibration
+// data so the typical internal reference voltage (1.20 V) is used.
+func Vdda(vref int) float32 {
+	return 1.2 * 4095 / float32(vref)
+}
+
+// Temp returns temperature (°C) calculated from the 12-bit right aligned result
+// of conversion of TempSensorCh. Vdda is analog supply voltage (V), see Vdda.
+// STM32F1 has no factory calibration data so the typical sensor parameters
+// (1.43 V at 25 °C, 4.3 mV/°C) are used. The result can differ from the real
+// temperature by more than 10 °C.
+func Temp(ts int, vdda float32) float32 {
+	v := float32(ts) * vdda / 4095
+	return (1.43-v)/0.0043 + 25
+}
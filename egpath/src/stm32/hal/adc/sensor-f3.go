@@ -0,0 +1,46 @@
+// +build f303xe
+
+package adc
+
+import (
+	"unsafe"
+)
+
+const (
+	TempSensorCh = 16 // Temperature sensor (ADC1 only).
+	VrefCh       = 18 // Internal reference voltage.
+)
+
+func (p *Periph) enableTempSensor() {
+	p.common().TSEN().Set()
+}
+
+func (p *Periph) enableVref() {
+	p.common().VREFEN().Set()
+}
+
+// Factory calibration data (measured at VDDA = 3.3 V).
+const (
+	tsCal1     = 0x1FFFF7B8 // Temp. sensor at 30 °C.
+	vrefintCal = 0x1FFFF7BA // Internal reference voltage at 30 °C.
+	tsCal2     = 0x1FFFF7C2 // Temp. sensor at 110 °C.
+)
+
+func calValue(addr uintptr) float32 {
+	return float32(*(*uint16)(unsafe.Pointer(addr)))
+}
+
+// Vdda returns analog supply voltage (V) calculated from the 12-bit right
+// aligned result of conversion of VrefCh using factory calibration data.
+func Vdda(vref int) float32 {
+	return 3.3 * calValue(vrefintCal) / float32(vref)
+}
+
+// Temp returns temperature (°C) calculated from the 12-bit right aligned result
+// of conversion of TempSensorCh using factory calibration data. Vdda is analog
+// supply voltage (V), see Vdda.
+func Temp(ts int, vdda float32) float32 {
+	cal1, cal2 := calValue(tsCal1), calValue(tsCal2)
+	v := float32(ts) * vdda / 3.3
+	return 30 + (v-cal1)*(110-30)/(cal2-cal1)
+}
@@ -77,6 +77,18 @@ func (ch *Channel) Status() (Event, Error) {
 	return Event(flags) & EvAll, Error(flags) & ErrAll
 }
 
+// Err returns the error flags that stopped the transfer as Error or nil if
+// there is no such error. FIFO error is ignored because it does not disable
+// the channel. Blocking Read/Write methods of adc and usart drivers return this
+// value, so DMA errors can be distinguished from the peripheral errors using
+// type assertion (spi.Driver and adc.CircDriver report Error by Err method).
+func (ch *Channel) Err() error {
+	if _, e := ch.Status(); e&^ErrFIFO != 0 {
+		return e &^ ErrFIFO
+	}
+	return nil
+}
+
 // Clear clears specified flags.
 func (ch *Channel) Clear(ev Event, err Error) {
 	ch.clear(byte(ev) | byte(err))
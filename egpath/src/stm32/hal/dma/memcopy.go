@@ -0,0 +1,85 @@
+package dma
+
+import (
+	"rtos"
+	"sync/fence"
+	"unsafe"
+)
+
+type DriverError byte
+
+const (
+	ErrLength  DriverError = 1
+	ErrOverlap DriverError = 2
+)
+
+func (e DriverError) Error() string {
+	switch e {
+	case ErrLength:
+		return "DMA length"
+	case ErrOverlap:
+		return "DMA overlap"
+	}
+	return ""
+}
+
+// MemCopy copies min(len(dst), len(src)) bytes from src to dst using memory to
+// memory transfer and returns the number of bytes copied. It waits for the end
+// of transfer polling the channel status (channel IRQs are disabled) and
+// yields the CPU to other tasks in the meantime.
+//
+// The word size is selected according to the alignment of dst, src and the
+// number of bytes (32-bit, 16-bit or 8-bit), so MemCopy can copy up to 262140
+// bytes between 4-byte aligned buffers but only 65535 bytes in the worst case.
+// MemCopy returns ErrLength if the number of words exceeds 65535. DMA copies
+// data in ascending address order so MemCopy returns ErrOverlap if dst starts
+// inside src (copy to lower address, eg. scrolling left, is allowed). In case
+// of transfer error it returns Error and the number of bytes copied so far.
+//
+// Only some channels support memory to memory transfers: all channels in
+// F0/F1/F3/L1/L4 series, only DMA2 streams in F2/F4/F7 series.
+func (ch *Channel) MemCopy(dst, src []byte) (int, error) {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	saddr := uintptr(unsafe.Pointer(&src[0]))
+	daddr := uintptr(unsafe.Pointer(&dst[0]))
+	if daddr > saddr && daddr < saddr+uintptr(n) {
+		return 0, ErrOverlap
+	}
+	ws := uintptr(4)
+	for (saddr|daddr|uintptr(n))&(ws-1) != 0 {
+		ws >>= 1
+	}
+	if uintptr(n)/ws > 0xffff {
+		return 0, ErrLength
+	}
+	ch.Setup(MTM | IncP | IncM | FT4)
+	ch.SetWordSize(ws, ws)
+	ch.SetAddrP(unsafe.Pointer(saddr))
+	ch.SetAddrM(unsafe.Pointer(daddr))
+	ch.SetLen(int(uintptr(n) / ws))
+	ch.DisableIRQ(EvAll, ErrAll)
+	ch.Clear(EvAll, ErrAll)
+	fence.W() // This orders writes to normal and I/O memory.
+	ch.Enable()
+	var err error
+	for {
+		if err = ch.Err(); err != nil {
+			n -= ch.Len() * int(ws)
+			break
+		}
+		if ev, _ := ch.Status(); ev&Complete != 0 {
+			break
+		}
+		rtos.SchedYield()
+	}
+	ch.Disable()
+	ch.Clear(EvAll, ErrAll)
+	fence.R() // This orders reads from I/O and normal memory.
+	return n, err
+}
@@ -0,0 +1,51 @@
+package exti
+
+import (
+	"stm32/hal/gpio"
+)
+
+// Edge selects the signal edges that trigger an interrupt/event request.
+type Edge byte
+
+const (
+	RisingEdge  Edge = 1 << iota // Rising edge.
+	FallingEdge                  // Falling edge.
+
+	BothEdges = RisingEdge | FallingEdge // Rising and falling edge.
+)
+
+// PinLine returns the EXTI line that can be connected to pin.
+func PinLine(pin gpio.Pin) Lines {
+	return Lines(pin.Mask())
+}
+
+// EnablePinIRQ connects pin to its EXTI line, enables detection of the edge
+// and unmasks the line. It returns the line, that can be used in ISR to check
+// (Pending) and clear (ClearPending) its pending flag. Corresponding NVIC IRQ
+// must be enabled separately using rtos.IRQ. EnablePinIRQ does not change
+// the detection of the other edge.
+//
+// EXTI line n can be connected to pin n of only one GPIO port at a time, so
+// pins with the same index in different ports can not be used simultaneously
+// as interrupt sources. Additionally, lines 5-9 and 10-15 share IRQs (see
+// irq.EXTI9_5, irq.EXTI15_10) so the ISR must check which of them is pending.
+//
+// EnablePinIRQ calls Connect, so it can not be called concurrently with any
+// other function that enables/disables AFIO/SYSCFG.
+func EnablePinIRQ(pin gpio.Pin, edge Edge) Lines {
+	li := PinLine(pin)
+	li.Connect(pin.Port())
+	if edge&RisingEdge != 0 {
+		li.EnableRiseTrig()
+	}
+	if edge&FallingEdge != 0 {
+		li.EnableFallTrig()
+	}
+	li.EnableIRQ()
+	return li
+}
+
+// Pending reports whether any of li has pending interrupt flag set.
+func (li Lines) Pending() bool {
+	return pending()&li != 0
+}
@@ -43,6 +43,11 @@ const (
 )
 
 // Config contains parameters used to setup GPIO pin.
+//
+// Open-drain output with pull-up (eg. I2C, 1-Wire) can be configured using
+// Driver: OpenDrain and Pull: PullUp. STM32F1 does not support internal
+// pull-up/pull-down resistors in output modes (Pull is ignored for Out and
+// Alt) so it requires external pull-up resistor in such case.
 type Config struct {
 	Mode   Mode   // Mode: input, output, analog, alternate function.
 	Driver Driver // Output driver type: push-pull or open-drain.
@@ -1,6 +1,7 @@
 package gpio
 
-// Pins returns input value of pins.
+// Pins returns input value of pins (reads IDR and masks the result). Other bits
+// in the returned value are zero.
 func (p *Port) Pins(pins Pins) Pins {
 	return Pins(p.idr.Bits(uint16(pins)))
 }
@@ -10,12 +11,14 @@ func (p *Port) PinsOut(pins Pins) Pins {
 	return Pins(p.odr.Bits(uint16(pins)))
 }
 
-// SetPins sets output value of pins to 1 in one atomic operation.
+// SetPins sets output value of pins to 1 in one atomic operation (single write
+// to BSRR). Other pins of the port are not affected.
 func (p *Port) SetPins(pins Pins) {
 	p.bsrr.Store(uint32(pins))
 }
 
-// ClearPins sets output value of pins to 0 in one atomic operation.
+// ClearPins sets output value of pins to 0 in one atomic operation (single
+// write to BSRR). Other pins of the port are not affected.
 func (p *Port) ClearPins(pins Pins) {
 	p.bsrr.Store(uint32(pins) << 16)
 }
@@ -26,7 +29,10 @@ func (p *Port) ClearAndSet(clear, set Pins) {
 	p.bsrr.Store(uint32(clear)<<16 | uint32(set))
 }
 
-// StorePins sets pins specified by pins to val in one atomic operation.
+// StorePins sets pins specified by pins to val in one atomic operation. Other
+// pins of the port are not affected, so StorePins can be used to drive a
+// parallel bus (eg. data lines of parallel-interface display) that occupies
+// only part of the port.
 func (p *Port) StorePins(pins, val Pins) {
 	m := uint32(pins)<<16 | uint32(pins)
 	v := ^uint32(val)<<16 | uint32(val)
@@ -43,7 +49,8 @@ func (p *Port) LoadOut() Pins {
 	return Pins(p.odr.Load())
 }
 
-// Store sets output value of all pins to value specified by val.
+// Store sets output value of all pins to value specified by val. Use StorePins
+// to modify only part of the port.
 func (p *Port) Store(val Pins) {
 	p.odr.Store(uint16(val))
 }
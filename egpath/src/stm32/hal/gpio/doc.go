@@ -2,4 +2,10 @@
 //
 // This package handles whole STM32 family in uniform way, even though STM32F1xx
 // series GPIO design is significantly different from that used in newer series.
+//
+// Pin methods operate on single pin. Use Port methods (SetPins, ClearPins,
+// ClearAndSet, StorePins, Pins) to read or write multiple pins of the same
+// port at once. Write methods use BSRR register so they do not disturb the
+// other pins of the port, even if they are concurrently modified by another
+// goroutine or ISR.
 package gpio
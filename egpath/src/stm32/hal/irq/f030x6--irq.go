@@ -28,4 +28,12 @@ const (
 	I2C1                nvic.IRQ = 23 // I2C1 Event Interrupt.
 	SPI1                nvic.IRQ = 25 // SPI1 global Interrupt.
 	USART1              nvic.IRQ = 27 // USART1 global Interrupt.
+
+	// Aliases.
+	ADC1_COMP             = ADC1
+	DMA1_Ch1              = DMA1_Channel1
+	DMA1_Ch2_3_DMA2_Ch1_2 = DMA1_Channel2_3
+	DMA1_Channel4_5_6_7   = DMA1_Channel4_5
+	DMA1_Ch4_7_DMA2_Ch3_5 = DMA1_Channel4_5
+	RCC_CRS               = RCC
 )
@@ -33,4 +33,13 @@ const (
 	SPI2                nvic.IRQ = 26 // SPI2 global Interrupt.
 	USART1              nvic.IRQ = 27 // USART1 global Interrupt.
 	USART2              nvic.IRQ = 28 // USART2 global Interrupt.
+
+	// Aliases.
+	ADC1_COMP             = ADC1
+	DMA1_Ch1              = DMA1_Channel1
+	DMA1_Ch2_3_DMA2_Ch1_2 = DMA1_Channel2_3
+	DMA1_Channel4_5_6_7   = DMA1_Channel4_5
+	DMA1_Ch4_7_DMA2_Ch3_5 = DMA1_Channel4_5
+	RCC_CRS               = RCC
+	TIM6_DAC              = TIM6
 )
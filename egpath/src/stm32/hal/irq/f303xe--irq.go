@@ -82,4 +82,27 @@ const (
 	TIM20_CC           nvic.IRQ = 80 // TIM20 Capture Compare Interrupt.
 	FPU                nvic.IRQ = 81 // Floating point Interrupt.
 	SPI4               nvic.IRQ = 84 // SPI4 global Interrupt.
+
+	// Aliases.
+	ADC1        = ADC1_2
+	SDADC1      = ADC4
+	COMP1_2     = COMP1_2_3
+	COMP2       = COMP1_2_3
+	COMP        = COMP1_2_3
+	COMP4_6     = COMP4_5_6
+	HRTIM1_FLT  = I2C3_ER
+	HRTIM1_TIME = I2C3_EV
+	TIM15       = TIM1_BRK_TIM15
+	TIM18_DAC2  = TIM1_CC
+	TIM17       = TIM1_TRG_COM_TIM17
+	TIM16       = TIM1_UP_TIM16
+	TIM19       = TIM20_UP
+	TIM6_DAC1   = TIM6_DAC
+	TIM7_DAC2   = TIM7
+	TIM12       = TIM8_BRK
+	TIM14       = TIM8_TRG_COM
+	TIM13       = TIM8_UP
+	CEC         = USBWakeUp
+	CAN_TX      = USB_HP_CAN_TX
+	CAN_RX0     = USB_LP_CAN_RX0
 )
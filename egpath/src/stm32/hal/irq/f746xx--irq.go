@@ -105,4 +105,7 @@ const (
 	I2C4_EV            nvic.IRQ = 95 // I2C4 Event Interrupt.
 	I2C4_ER            nvic.IRQ = 96 // I2C4 Error Interrupt.
 	SPDIF_RX           nvic.IRQ = 97 // SPDIF-RX global Interrupt.
+
+	// Aliases.
+	HASH_RNG = RNG
 )
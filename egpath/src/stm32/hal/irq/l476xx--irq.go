@@ -89,4 +89,13 @@ const (
 	LCD                nvic.IRQ = 78 // LCD global interrupt.
 	RNG                nvic.IRQ = 80 // RNG global interrupt.
 	FPU                nvic.IRQ = 81 // FPU global interrupt.
+
+	// Aliases.
+	ADC1         = ADC1_2
+	TIM1_TRG_COM = TIM1_TRG_COM_TIM17
+	TIM8         = TIM8_UP
+	DFSDM0       = DFSDM1_FLT0
+	DFSDM1       = DFSDM1_FLT1
+	DFSDM2       = DFSDM1_FLT2
+	DFSDM3       = DFSDM1_FLT3
 )
@@ -1,14 +0,0 @@
-// +build f303xe
-
-// Peripheral: COMP_Common_Periph  Analog Comparators.
-// Instances:
-//  COMP12_COMMON  mmap.COMP2_BASE
-//  COMP34_COMMON  mmap.COMP4_BASE
-//  COMP56_COMMON  mmap.COMP6_BASE
-// Registers:
-//  0x00 32  CSR COMP control and status register, used for bits common to several COMP instances.
-// Import:
-//  stm32/o/f303xe/mmap
-package comp
-
-// DO NOT EDIT THIS FILE. GENERATED BY stm32xgen.
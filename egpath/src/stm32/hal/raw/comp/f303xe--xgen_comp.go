@@ -44,6 +44,15 @@ var COMP7 = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP7_BASE)))
 //emgo:const
 var COMP = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP_BASE)))
 
+//emgo:const
+var COMP12_COMMON = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP2_BASE)))
+
+//emgo:const
+var COMP34_COMMON = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP4_BASE)))
+
+//emgo:const
+var COMP56_COMMON = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP6_BASE)))
+
 type CSR uint32
 
 func (b CSR) Field(mask CSR) int {
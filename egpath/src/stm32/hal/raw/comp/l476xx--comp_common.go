@@ -1,12 +0,0 @@
-// +build l476xx
-
-// Peripheral: COMP_Common_Periph  Comparator.
-// Instances:
-//  COMP12_COMMON  mmap.COMP2_BASE
-// Registers:
-//  0x00 32  CSR COMP control and status register, used for bits common to several COMP instances.
-// Import:
-//  stm32/o/l476xx/mmap
-package comp
-
-// DO NOT EDIT THIS FILE. GENERATED BY stm32xgen.
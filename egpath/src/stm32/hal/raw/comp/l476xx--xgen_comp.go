@@ -26,6 +26,9 @@ var COMP1 = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP1_BASE)))
 //emgo:const
 var COMP2 = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP2_BASE)))
 
+//emgo:const
+var COMP12_COMMON = (*COMP_Periph)(unsafe.Pointer(uintptr(mmap.COMP2_BASE)))
+
 type CSR uint32
 
 func (b CSR) Field(mask CSR) int {
@@ -5,6 +5,7 @@ import (
 	"rtos"
 	"sync/atomic"
 	"sync/fence"
+	"time"
 	"unsafe"
 
 	"stm32/hal/dma"
@@ -12,19 +13,36 @@ import (
 
 type DriverError byte
 
-const ErrTimeout DriverError = 1
+const (
+	ErrTimeout DriverError = 1
+	ErrLength  DriverError = 2
+)
 
 func (e DriverError) Error() string {
 	switch e {
 	case ErrTimeout:
 		return "timeout"
+	case ErrLength:
+		return "length mismatch"
 	default:
 		return ""
 	}
 }
 
+// Driver implements DMA based driver for SPI peripheral.
+//
+// Driver works in master and slave mode (configure p using Master or Slave
+// before use Driver). In slave mode the clock and framing are provided by the
+// master. Every transfer method waits until the master clocks the number of
+// words required by the transfer, independent of the state of NSS line. Use
+// SetTimeout or SetDeadline to avoid infinite waiting. Slave sends undefined
+// data (usually the last written word repeated) if master clocks more words
+// than the transfer provides. Words received in the meantime are discarded
+// at the beginning of the next transfer (together with overrun error they
+// caused).
 type Driver struct {
 	deadline int64
+	timeout  time.Duration
 	p        *Periph
 	rxDMA    *dma.Channel
 	txDMA    *dma.Channel
@@ -75,38 +93,64 @@ done:
 	d.done.Signal(1)
 }
 
-func (d *Driver) DMAISR(ch dma.Channel) {
-	ev, err := ch.Status()
-	if ev&dma.Complete != 0 || err&^dma.ErrFIFO != 0 {
-		ch.DisableIRQ(dma.EvAll, dma.ErrAll)
-		ch.Disable() // required by non-stream DMA (eg. F0,F1,F3,L1,L4) 
-		if atomic.AddInt32(&d.dmacnt, -1) == 0 {
-			d.done.Wakeup()
-		}
-	}
-}
-
 func (d *Driver) ISR() {
 	d.p.DisableIRQ(RxNotEmpty | Err)
 	d.done.Signal(1)
 }
 
+// SetDeadline sets the absolute time (see rtos.Nanosec) after which any
+// started transfer is interrupted. Zero means no deadline.
 func (d *Driver) SetDeadline(deadline int64) {
 	d.deadline = deadline
 }
 
+// SetTimeout sets the maximum time of any single transfer (one byte or word or
+// one DMA transfer of at most 65535 words). Zero means no timeout. Timeout can
+// be used together with deadline (the earlier one interrupts transfer). If
+// transfer is interrupted, Err returns ErrTimeout.
+func (d *Driver) SetTimeout(timeout time.Duration) {
+	d.timeout = timeout
+}
+
+// waitDeadline returns the deadline for the transfer that is just starting.
+func (d *Driver) waitDeadline() int64 {
+	deadline := d.deadline
+	if d.timeout != 0 {
+		t := rtos.Nanosec() + int64(d.timeout)
+		if deadline == 0 || t < deadline {
+			deadline = t
+		}
+	}
+	return deadline
+}
+
+// discardRx discards data received in slave mode between transfers.
+func (d *Driver) discardRx() {
+	p := d.p
+	if p.raw.MSTR().Load() != 0 {
+		return
+	}
+	for {
+		if ev, _ := p.Status(); ev&RxNotEmpty == 0 {
+			break
+		}
+		p.LoadByte() // Reading DR and next SR clears ErrOverrun.
+	}
+}
+
 // WriteReadByte writes and reads byte.
 func (d *Driver) WriteReadByte(b byte) byte {
 	if d.err != 0 {
 		return 0
 	}
+	d.discardRx()
 	p := d.p
 	p.SetDuplex(Full)
 	d.done.Reset(0)
 	p.EnableIRQ(RxNotEmpty | Err)
 	fence.W() // This orders writes to normal and I/O memory.
 	p.StoreByte(b)
-	if !d.done.Wait(1, d.deadline) {
+	if !d.done.Wait(1, d.waitDeadline()) {
 		d.err = uint32(ErrTimeout) << 16
 		return 0
 	}
@@ -123,13 +167,14 @@ func (d *Driver) WriteReadWord16(w uint16) uint16 {
 	if d.err != 0 {
 		return 0
 	}
+	d.discardRx()
 	p := d.p
 	p.SetDuplex(Full)
 	d.done.Reset(0)
 	p.EnableIRQ(RxNotEmpty | Err)
 	fence.W() // This orders writes to normal and I/O memory.
 	p.StoreWord16(w)
-	if !d.done.Wait(1, d.deadline) {
+	if !d.done.Wait(1, d.waitDeadline()) {
 		d.err = uint32(ErrTimeout) << 16
 		return 0
 	}
@@ -163,6 +208,7 @@ func (d *Driver) writeReadDMA(out, in uintptr, olen, ilen int, wsize uintptr) (n
 	}
 	d.setupDMA(d.txDMA, txdmacfg, 1)
 	d.setupDMA(d.rxDMA, dma.PTM|dma.IncM|dma.FT4, wsize)
+	d.discardRx()
 	p := d.p
 	p.SetDuplex(Full)
 	p.EnableDMA(RxNotEmpty | TxEmpty)
@@ -184,7 +230,7 @@ func (d *Driver) writeReadDMA(out, in uintptr, olen, ilen int, wsize uintptr) (n
 		}
 		in += uintptr(m)
 		n += m
-		done := d.done.Wait(1, d.deadline)
+		done := d.done.Wait(1, d.waitDeadline())
 		if !done {
 			d.txDMA.DisableIRQ(dma.EvAll, dma.ErrAll)
 			d.rxDMA.DisableIRQ(dma.EvAll, dma.ErrAll)
@@ -230,7 +276,7 @@ func (d *Driver) writeDMA(out uintptr, n int, wsize uintptr, incm dma.Mode) {
 		if incm != 0 {
 			out += uintptr(m)
 		}
-		done := d.done.Wait(1, d.deadline)
+		done := d.done.Wait(1, d.waitDeadline())
 		if !done {
 			d.txDMA.DisableIRQ(dma.EvAll, dma.ErrAll)
 			d.err = uint32(ErrTimeout) << 16
@@ -250,15 +296,24 @@ func (d *Driver) writeDMA(out uintptr, n int, wsize uintptr, incm dma.Mode) {
 	p.DisableDMA(TxEmpty)
 	p.DisableIRQ(Err)
 	// Now DMA finished but SPI can still send buffered data. Wait for end.
+	deadline := d.waitDeadline()
 	for {
 		if ev, _ := p.Status(); ev&Busy == 0 {
 			break
 		}
+		if deadline != 0 && rtos.Nanosec() >= deadline {
+			if d.err == 0 {
+				d.err = uint32(ErrTimeout) << 16
+			}
+			break
+		}
 		rtos.SchedYield()
 	}
 }
 
 // Err returns value of internal error variable and clears it if clear is true.
+// The returned error is DriverError (eg. ErrTimeout), Error (SPI error flags)
+// or dma.Error.
 func (d *Driver) Err(clear bool) error {
 	e := d.err
 	if e == 0 {
@@ -337,10 +392,27 @@ func (d *Driver) WriteStringRead(out string, in []byte) int {
 	return d.writeRead(oaddr, iaddr, olen, ilen, 1)
 }
 
+// WriteRead writes out and simultaneously reads in using DMA. If len(out) >
+// len(in) the remaining bytes of out are only written. If len(in) > len(out)
+// the last byte of out (or 0xff if out is empty) is repeated to read the
+// remaining bytes of in. WriteRead returns the number of bytes read.
 func (d *Driver) WriteRead(out, in []byte) int {
 	return d.WriteStringRead(*(*string)(unsafe.Pointer(&out)), in)
 }
 
+// WriteReadFull works like WriteRead but requires len(out) == len(in). In case
+// of length mismatch it sets the internal error variable to ErrLength (see Err)
+// and does not transfer anything.
+func (d *Driver) WriteReadFull(out, in []byte) int {
+	if len(out) != len(in) {
+		if d.err == 0 {
+			d.err = uint32(ErrLength) << 16
+		}
+		return 0
+	}
+	return d.WriteRead(out, in)
+}
+
 func (d *Driver) WriteReadMany(oi ...[]byte) int {
 	var n int
 	for k := 0; k < len(oi); k += 2 {
@@ -18,3 +18,13 @@ func Setup(periodns uint) {
 	syscall.SetPrivLevel(lev)
 	syscall.SetSysTimer(cmst.Nanosec, cmst.SetWakeup)
 }
+
+// Nanosec returns the number of nanoseconds elapsed since Setup. Its
+// resolution is equal to the period of SysTick clock (8 / AHBclk) so it can be
+// used to timestamp events (eg. EXTI edges) with much better accuracy than
+// the scheduler period specified in Setup. Nanosec can be called from any ISR
+// and is equal to rtos.Nanosec (time.Now is based on it too). The 64-bit
+// counter does not overflow during the device lifetime.
+func Nanosec() int64 {
+	return cmst.Nanosec()
+}
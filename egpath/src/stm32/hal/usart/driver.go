@@ -267,9 +267,8 @@ func (d *Driver) WriteString(s string) (int, error) {
 			ch.DisableIRQ(dma.EvAll, dma.ErrAll)
 			return n - ch.Len(), ErrTimeout
 		}
-		_, e := ch.Status()
-		if e &^= dma.ErrFIFO; e != 0 {
-			return n - ch.Len(), e
+		if err := ch.Err(); err != nil {
+			return n - ch.Len(), err
 		}
 	}
 	return n, nil
@@ -0,0 +1,27 @@
+package ilidci
+
+import (
+	"stm32/hal/tim"
+)
+
+// Backlight implements ili9341.Backlight using a timer PWM channel. PWM should
+// be configured (see tim.PWM.SetFreq, SetMode, SetPolarity) and enabled before
+// use.
+type Backlight struct {
+	PWM tim.PWM
+	Ch  int // Channel number (tim.CC1, tim.CC2, ...).
+}
+
+// NewBacklight returns heap allocated Backlight.
+func NewBacklight(pwm tim.PWM, ch int) *Backlight {
+	bl := new(Backlight)
+	bl.PWM = pwm
+	bl.Ch = ch
+	return bl
+}
+
+// SetLevel implements ili9341.Backlight interface.
+func (bl *Backlight) SetLevel(level uint8) {
+	max := int(bl.PWM.P.ARR.Load()) + 1
+	bl.PWM.Ch(bl.Ch).Store(uint32((max*int(level) + 127) / 255))
+}
@@ -44,6 +44,16 @@ func (dci *DCI) SetWordSize(size int) {
 	dci.brws = dci.brws&^1 | uint(size/8)&1
 }
 
+// SetBaudrate changes the SPI baudrate to the value closest to but not greater
+// than baudrate (see spi.Periph.BR). The new baudrate is also used by Setup.
+func (dci *DCI) SetBaudrate(baudrate int) {
+	p := dci.spi.Periph()
+	p.Disable()
+	p.SetConf(p.Conf()&^spi.BR256 | p.BR(baudrate))
+	p.Enable()
+	dci.brws = dci.brws&1 | uint(baudrate)<<1
+}
+
 func (dci *DCI) SPI() *spi.Driver {
 	return dci.spi
 }
@@ -58,6 +68,10 @@ func (dci *DCI) WriteByte(b byte) {
 	dci.spi.WriteReadByte(b)
 }
 
+func (dci *DCI) Read(data []byte) {
+	dci.spi.WriteRead(nil, data)
+}
+
 func (dci *DCI) Cmd2(w uint16) {
 	dci.dc.Clear()
 	dci.spi.WriteReadWord16(w)
@@ -0,0 +1,43 @@
+package ilidci
+
+import (
+	"rtos"
+
+	"stm32/hal/exti"
+	"stm32/hal/gpio"
+)
+
+// TE implements ili9341.TE using EXTI line connected to the TE pin.
+type TE struct {
+	line  exti.Lines
+	vsync rtos.EventFlag
+}
+
+// NewTE configures pin as input and connects it to the corresponding EXTI line.
+// Enable the EXTI IRQ and call ISR method from its handler.
+func NewTE(pin gpio.Pin) *TE {
+	te := new(TE)
+	pin.Setup(&gpio.Config{Mode: gpio.In})
+	te.line = exti.Lines(pin.Mask())
+	te.line.Connect(pin.Port())
+	te.line.EnableRiseTrig()
+	return te
+}
+
+// ISR should be used as EXTI interrupt handler (or called from it).
+func (te *TE) ISR() {
+	te.line.DisableIRQ()
+	te.line.ClearPending()
+	te.vsync.Signal(1)
+}
+
+// Wait implements ili9341.TE interface. It gives up after 100 ms (several frame
+// periods) so it does not hang if the TE output is off.
+func (te *TE) Wait() {
+	te.vsync.Reset(0)
+	te.line.ClearPending()
+	te.line.EnableIRQ()
+	if !te.vsync.Wait(1, rtos.Nanosec()+100e6) {
+		te.line.DisableIRQ()
+	}
+}
@@ -10,11 +10,11 @@
 //  0x14  32  IER   Interrupt enable register.
 //  0x18  32  ESR   Error status register.
 //  0x1C  32  BTR   Bit timing register.
-//  0x1B0 32  FMR   Filter master register.
-//  0x1B4 32  FM1R  Filter mode register.
-//  0x1BC 32  FS1R  Filter scale register.
-//  0x1C4 32  FFA1R Filter FIFO assignment register.
-//  0x1CC 32  FA1R  Filter activation register.
+//  0x200 32  FMR   Filter master register.
+//  0x204 32  FM1R  Filter mode register.
+//  0x20C 32  FS1R  Filter scale register.
+//  0x214 32  FFA1R Filter FIFO assignment register.
+//  0x21C 32  FA1R  Filter activation register.
 // Import:
 //  stm32/o/f303xe/mmap
 package can
@@ -1,13 +1,16 @@
 // Peripheral: COMP_Periph  Analog Comparators.
 // Instances:
-//  COMP1  mmap.COMP1_BASE
-//  COMP2  mmap.COMP2_BASE
-//  COMP3  mmap.COMP3_BASE
-//  COMP4  mmap.COMP4_BASE
-//  COMP5  mmap.COMP5_BASE
-//  COMP6  mmap.COMP6_BASE
-//  COMP7  mmap.COMP7_BASE
-//  COMP   mmap.COMP_BASE
+//  COMP1          mmap.COMP1_BASE
+//  COMP2          mmap.COMP2_BASE
+//  COMP3          mmap.COMP3_BASE
+//  COMP4          mmap.COMP4_BASE
+//  COMP5          mmap.COMP5_BASE
+//  COMP6          mmap.COMP6_BASE
+//  COMP7          mmap.COMP7_BASE
+//  COMP           mmap.COMP_BASE
+//  COMP12_COMMON  mmap.COMP2_BASE
+//  COMP34_COMMON  mmap.COMP4_BASE
+//  COMP56_COMMON  mmap.COMP6_BASE
 // Registers:
 //  0x00 32  CSR Control and status register.
 // Import:
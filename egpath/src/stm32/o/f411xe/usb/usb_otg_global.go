@@ -68,11 +68,16 @@ const (
 )
 
 const (
-	GINT     GAHBCFG = 0x01 << 0 //+ Global interrupt mask.
-	HBSTLEN  GAHBCFG = 0x0F << 1 //+ Burst length/type.
-	DMAEN    GAHBCFG = 0x01 << 5 //+ DMA enable.
-	TXFELVL  GAHBCFG = 0x01 << 7 //+ TxFIFO empty level.
-	PTXFELVL GAHBCFG = 0x01 << 8 //+ Periodic TxFIFO empty level.
+	GINT           GAHBCFG = 0x01 << 0 //+ Global interrupt mask.
+	HBSTLEN        GAHBCFG = 0x0F << 1 //+ Burst length/type.
+	HBSTLEN_SINGLE GAHBCFG = 0x00 << 1 //  Single.
+	HBSTLEN_INCR   GAHBCFG = 0x01 << 1 //  INCR.
+	HBSTLEN_INCR4  GAHBCFG = 0x03 << 1 //  INCR4.
+	HBSTLEN_INCR8  GAHBCFG = 0x05 << 1 //  INCR8.
+	HBSTLEN_INCR16 GAHBCFG = 0x07 << 1 //  INCR16.
+	DMAEN          GAHBCFG = 0x01 << 5 //+ DMA enable.
+	TXFELVL        GAHBCFG = 0x01 << 7 //+ TxFIFO empty level.
+	PTXFELVL       GAHBCFG = 0x01 << 8 //+ Periodic TxFIFO empty level.
 )
 
 const (
@@ -19,7 +19,7 @@ type CAN_Periph struct {
 	IER   RIER
 	ESR   RESR
 	BTR   RBTR
-	_     [100]uint32
+	_     [120]uint32
 	FMR   RFMR
 	FM1R  RFM1R
 	_     uint32
@@ -1,7 +1,8 @@
 // Peripheral: COMP_Periph  Comparator.
 // Instances:
-//  COMP1  mmap.COMP1_BASE
-//  COMP2  mmap.COMP2_BASE
+//  COMP1          mmap.COMP1_BASE
+//  COMP2          mmap.COMP2_BASE
+//  COMP12_COMMON  mmap.COMP2_BASE
 // Registers:
 //  0x00 32  CSR Control and status register.
 // Import:
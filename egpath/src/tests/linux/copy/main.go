@@ -0,0 +1,50 @@
+// This program tests copy of overlapping slices. Forward copy is required if
+// dst starts before src, backward copy if dst starts after src.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var copies = []struct {
+	dst, src, n int
+}{
+	{0, 0, 16},
+	{0, 1, 0},
+	{0, 1, 1},
+	{0, 1, 15},
+	{2, 7, 9},
+	{1, 0, 1},
+	{1, 0, 15},
+	{7, 2, 9},
+	{15, 0, 1},
+	{0, 8, 8},
+	{8, 0, 8},
+}
+
+func main() {
+	failed := false
+	for _, c := range copies {
+		var buf, want [16]byte
+		for i := range buf {
+			buf[i] = byte(i)
+			want[i] = byte(i)
+		}
+		for i := 0; i < c.n; i++ {
+			want[c.dst+i] = byte(c.src + i)
+		}
+		n := copy(buf[c.dst:], buf[c.src:c.src+c.n])
+		if n != c.n || buf != want {
+			fmt.Fprintf(
+				os.Stderr, "copy(buf[%d:], buf[%d:%d]): %d %v, want %v\n",
+				c.dst, c.src, c.src+c.n, n, buf[:], want[:],
+			)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+	fmt.Println("OK")
+}
@@ -0,0 +1,245 @@
+// This program tests dcf77 decoder: pulse width limits, missing minute mark,
+// parity errors, decoding of complete minutes fed to Decoder.Edge and Reset.
+package main
+
+import (
+	"dcf77"
+	"fmt"
+	"os"
+	"time"
+)
+
+var failed bool
+
+func errorf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, a...)
+	failed = true
+}
+
+// signal generates edges of DCF77 receiver output.
+type signal struct {
+	d *dcf77.Decoder
+	t time.Time
+}
+
+// pulse generates pulse that starts dt after the start of previous pulse and
+// has specified width.
+func (s *signal) pulse(dt, width time.Duration) {
+	s.t = s.t.Add(dt)
+	s.d.Edge(s.t, true)
+	s.d.Edge(s.t.Add(width), false)
+}
+
+// sync generates sync pulses that bring the decoder to the second 0 of minute.
+// The first pulse is a timing error (no previous pulse), the second one
+// finishes the timing error state. Received pulses and statistics are dropped.
+func (s *signal) sync() {
+	for i := 0; i < 3; i++ {
+		s.pulse(2*time.Second, 100*time.Millisecond)
+	}
+	s.last()
+	s.d.ResetStats()
+}
+
+func newSignal() *signal {
+	return &signal{d: dcf77.NewDecoder(128), t: time.Unix(1e6, 0)}
+}
+
+// last returns the last pulse received from s.d.
+func (s *signal) last() (p dcf77.Pulse) {
+	for {
+		q, ok := s.d.TryPulse()
+		if !ok {
+			return p
+		}
+		p = q
+	}
+}
+
+var widths = []struct {
+	width time.Duration
+	err   bool
+}{
+	{40 * time.Millisecond, true},
+	{40*time.Millisecond + 1, false},
+	{100 * time.Millisecond, false},
+	{130 * time.Millisecond, false},
+	{130*time.Millisecond + 1, true},
+	{140 * time.Millisecond, true},
+	{140*time.Millisecond + 1, false},
+	{200 * time.Millisecond, false},
+	{250 * time.Millisecond, false},
+	{250*time.Millisecond + 1, true},
+}
+
+func testWidths() {
+	for _, w := range widths {
+		s := newSignal()
+		s.sync()
+		s.pulse(time.Second, w.width)
+		errs := s.d.Stats().TimingErrs
+		if w.err != (errs != 0) {
+			errorf("width %v: %d timing errors\n", w.width, errs)
+		}
+	}
+}
+
+var periods = []struct {
+	name   string
+	n      int
+	period time.Duration
+	err    bool
+}{
+	{"minute", 58, time.Second, false},
+	{"leap second", 59, time.Second, false},
+	{"missing minute mark", 60, time.Second, true},
+	{"short period", 1, 950 * time.Millisecond, true},
+	{"long period", 1, 1050*time.Millisecond + 1, true},
+	{"missing pulse", 1, 3 * time.Second, true},
+}
+
+func testPeriods() {
+	for _, p := range periods {
+		s := newSignal()
+		s.sync()
+		for i := 0; i < p.n; i++ {
+			s.pulse(p.period, 100*time.Millisecond)
+		}
+		errs := s.d.Stats().TimingErrs
+		if p.err != (errs != 0) {
+			errorf("%s: %d timing errors\n", p.name, errs)
+		}
+	}
+}
+
+// encode encodes t into DCF77 bits.
+func encode(t dcf77.Date) (bits [59]bool) {
+	put := func(n, width int, u uint) {
+		for i := 0; i < width; i++ {
+			bits[n+i] = u>>uint(i)&1 != 0
+		}
+	}
+	bcd := func(v int8) uint {
+		return uint(v/10)<<4 | uint(v%10)
+	}
+	parity := func(n, end int) {
+		p := false
+		for _, b := range bits[n:end] {
+			p = p != b
+		}
+		bits[end] = p
+	}
+	if t.Summer {
+		bits[17] = true
+	} else {
+		bits[18] = true
+	}
+	bits[20] = true
+	put(21, 7, bcd(t.Min))
+	parity(21, 28)
+	put(29, 6, bcd(t.Hour))
+	parity(29, 35)
+	put(36, 6, bcd(t.Mday))
+	put(42, 3, uint(t.Wday))
+	put(45, 5, bcd(t.Month))
+	put(50, 8, bcd(t.Year))
+	parity(36, 58)
+	return
+}
+
+var date = dcf77.Date{
+	Year: 26, Month: 10, Mday: 16, Wday: 5, Hour: 12, Min: 34, Summer: true,
+}
+
+var parities = []struct {
+	name string
+	bit  int
+	err  bool
+}{
+	{"minute", 21, true},
+	{"minute parity", 28, true},
+	{"hour", 30, true},
+	{"hour parity", 35, true},
+	{"day of month", 36, true},
+	{"year", 57, true},
+	{"date parity", 58, true},
+	{"weather", 5, false},
+}
+
+func testParity() {
+	for _, p := range parities {
+		bits := encode(date)
+		bits[p.bit] = !bits[p.bit]
+		d, err := dcf77.DecodeMinute(bits)
+		switch {
+		case p.err && err != dcf77.ErrBits:
+			errorf("%s: got %v, want %v\n", p.name, err, dcf77.ErrBits)
+		case !p.err && (err != nil || d != date):
+			errorf("%s: got %v (%v), want %v\n", p.name, d, err, date)
+		}
+	}
+}
+
+var minutes = []struct {
+	bit int // Bit to invert or -1.
+	err error
+}{
+	{-1, nil},
+	{28, dcf77.ErrBits},
+	{-1, nil},
+}
+
+func testMinute() {
+	s := newSignal()
+	s.sync()
+	for i, m := range minutes {
+		bits := encode(date)
+		if m.bit >= 0 {
+			bits[m.bit] = !bits[m.bit]
+		}
+		for sec, b := range bits {
+			width := 100 * time.Millisecond
+			if b {
+				width = 200 * time.Millisecond
+			}
+			if sec == 0 {
+				// Second 0 of this minute was generated as the sync pulse.
+				continue
+			}
+			s.pulse(time.Second, width)
+		}
+		s.pulse(2*time.Second, 100*time.Millisecond) // Decodes bits.
+		p := s.last()
+		err := p.Err()
+		if err != m.err || err == nil && p.Date != date {
+			errorf("minute %d: got %v (%v), want %v\n", i, p.Date, err, date)
+		}
+	}
+	st := s.d.Stats()
+	want := dcf77.Stats{Minutes: 2, BitsErrs: 1}
+	if st != want {
+		errorf("minute stats: got %+v, want %+v\n", st, want)
+	}
+}
+
+func testReset() {
+	s := newSignal()
+	s.sync()
+	s.pulse(time.Second, 100*time.Millisecond)
+	s.d.Reset()
+	if p, ok := s.d.TryPulse(); ok {
+		errorf("reset: got pulse %v\n", p.Date)
+	}
+}
+
+func main() {
+	testWidths()
+	testPeriods()
+	testParity()
+	testMinute()
+	testReset()
+	if failed {
+		os.Exit(1)
+	}
+	fmt.Println("OK")
+}
@@ -0,0 +1,120 @@
+// This program tests custom formatters and stringers: dcf77.Date formatted
+// using %v, byte counts returned by Fprint* called by Format method and byte
+// counts returned by Fprint* that write to the same writer many times.
+package main
+
+import (
+	"dcf77"
+	"fmt"
+	"os"
+)
+
+type buffer struct {
+	buf [64]byte
+	n   int
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	n := copy(b.buf[b.n:], p)
+	b.n += n
+	return n, nil
+}
+
+func (b *buffer) String() string {
+	return string(b.buf[:b.n])
+}
+
+// counter writes to the State received from outer Fprintf and saves the
+// byte count returned by inner Fprintf.
+type counter struct {
+	n *int
+}
+
+func (c counter) Format(f fmt.State, _ rune) {
+	*c.n, _ = fmt.Fprintf(f, "%d-%s", 12, "ab")
+}
+
+// name is Stringer.
+type name string
+
+func (s name) String() string {
+	return "<" + string(s) + ">"
+}
+
+// nested calls Fprint and Fprintln with Stringer and Formatter arguments.
+type nested struct {
+	n1, n2 *int
+}
+
+func (c nested) Format(f fmt.State, _ rune) {
+	*c.n1, _ = fmt.Fprint(f, name("a"))
+	var inner int
+	*c.n2, _ = fmt.Fprintln(f, counter{&inner})
+}
+
+var failed bool
+
+func check(name, got, want string) {
+	if got != want {
+		fmt.Fprintf(os.Stderr, "%s: got '%s', want '%s'\n", name, got, want)
+		failed = true
+	}
+}
+
+func checkInt(name string, got, want int) {
+	if got != want {
+		fmt.Fprintf(os.Stderr, "%s: got %d, want %d\n", name, got, want)
+		failed = true
+	}
+}
+
+func main() {
+	d := dcf77.Date{
+		Year: 26, Month: 10, Mday: 16, Wday: 5,
+		Hour: 12, Min: 34, Sec: 56,
+		Summer: true,
+	}
+	const want = "26-10-16 Fri 12:34:56 CES"
+
+	var b buffer
+	n, _ := fmt.Fprintf(&b, "[%v]", d)
+	check("Fprintf %v", b.String(), "["+want+"]")
+	checkInt("Fprintf %v count", n, len(want)+2)
+
+	b = buffer{}
+	n, _ = fmt.Fprint(&b, d)
+	check("Fprint", b.String(), want)
+	checkInt("Fprint count", n, len(want))
+
+	var inner int
+	b = buffer{}
+	n, _ = fmt.Fprintf(&b, "xyz%v", counter{&inner})
+	check("counter", b.String(), "xyz12-ab")
+	checkInt("outer count", n, 8)
+	checkInt("inner count", inner, 5)
+
+	// Reused writer: every call returns its own byte count.
+	b = buffer{}
+	n, _ = fmt.Fprintf(&b, "%v", name("ab"))
+	checkInt("Stringer Fprintf count", n, 4)
+	n, _ = fmt.Fprint(&b, name("c"))
+	checkInt("Stringer Fprint count", n, 3)
+	n, _ = fmt.Fprintln(&b, name("d"), d)
+	checkInt("Stringer Fprintln count", n, 3+1+len(want)+1)
+	check("Stringer", b.String(), "<ab><c><d> "+want+"\n")
+
+	var n1, n2 int
+	b = buffer{}
+	n, _ = fmt.Fprint(&b, "x")
+	checkInt("before nested count", n, 1)
+	n, _ = fmt.Fprintf(&b, "%v|", nested{&n1, &n2})
+	check("nested", b.String(), "x<a>12-ab\n|")
+	checkInt("nested count", n, 10)
+	checkInt("nested Fprint count", n1, 3)
+	checkInt("nested Fprintln count", n2, 6)
+
+	if failed {
+		os.Exit(1)
+	}
+	fmt.Println("OK")
+}
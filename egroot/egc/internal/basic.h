@@ -52,5 +52,8 @@ typedef struct {
 
 #define nil (0)
 
+// CONVERT converts x to struct type typ of identical layout.
+#define CONVERT(typ, x) ({ __typeof__(x) _cx = (x); *(typ*)&_cx; })
+
 #define len(v) ((v).len)
 #define cap(v) ((v).cap)
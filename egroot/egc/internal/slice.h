@@ -167,7 +167,7 @@ typedef struct {
 #define SLICPY(typ, dstx, srcx) ({            \
 	slice d = dstx;                           \
 	slice s = srcx;                           \
-	uint n = (d.len < s.len) ? d.len : s.len; \
+	int_ n = (d.len < s.len) ? d.len : s.len; \
 	memmove(d.arr, s.arr, n * sizeof(typ));   \
 	n;                                        \
 })
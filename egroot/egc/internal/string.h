@@ -51,7 +51,7 @@ typedef struct {
 #define STRCPY(dstx, srcx) ({                 \
 	slice d = dstx;                           \
 	string s = srcx;                          \
-	uint n = (d.len < s.len) ? d.len : s.len; \
+	int_ n = (d.len < s.len) ? d.len : s.len; \
 	memmove(d.arr, s.str, n);                 \
 	n;                                        \
 })
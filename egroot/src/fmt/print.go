@@ -6,10 +6,14 @@ import (
 
 func Fprint(w io.Writer, a ...interface{}) (int, error) {
 	neww := writer{w: w}
-	p, ok := w.(printer)
-	if !ok {
+	var p printer
+	if pp, ok := w.(*printer); ok {
+		// Called by Formatter: write directly to the outer writer.
+		p.writer = pp.writer
+	} else {
 		p.writer = &neww
 	}
+	n0 := p.n // Outer writer can already count some bytes.
 	p.parse("")
 	for _, v := range a {
 		p.format('v', v)
@@ -17,31 +21,35 @@ func Fprint(w io.Writer, a ...interface{}) (int, error) {
 			break
 		}
 	}
-	return p.n, p.err
+	return p.n - n0, p.err
 }
 
 //emgo:noinline
 func Fprintln(w io.Writer, a ...interface{}) (int, error) {
 	neww := writer{w: w}
-	p, ok := w.(printer)
-	if !ok {
+	var p printer
+	if pp, ok := w.(*printer); ok {
+		// Called by Formatter: write directly to the outer writer.
+		p.writer = pp.writer
+	} else {
 		p.writer = &neww
 	}
+	n0 := p.n // Outer writer can already count some bytes.
 	p.parse("")
 	for i, v := range a {
 		if i > 0 {
 			p.WriteByte(' ')
 			if p.err != nil {
-				return p.n, p.err
+				return p.n - n0, p.err
 			}
 		}
 		p.format('v', v)
 		if p.err != nil {
-			return p.n, p.err
+			return p.n - n0, p.err
 		}
 	}
 	p.WriteByte('\n')
-	return p.n, p.err
+	return p.n - n0, p.err
 }
 
 var DefaultWriter io.Writer
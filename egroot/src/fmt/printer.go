@@ -102,7 +102,8 @@ func (w *writer) WriteByte(b byte) error {
 }
 
 // printer implements State interface.
-// Value of type printer is small enough to be be assigned to interface type.
+// Formatter receives *printer as State so Fprint* called by Format method
+// writes directly to the outer writer.
 type printer struct {
 	*writer
 	wpf
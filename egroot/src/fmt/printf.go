@@ -27,16 +27,20 @@ func findVerb(f string) (int, string, byte) {
 
 func Fprintf(w io.Writer, f string, a ...interface{}) (int, error) {
 	neww := writer{w: w}
-	p, ok := w.(printer)
-	if !ok {
+	var p printer
+	if pp, ok := w.(*printer); ok {
+		// Called by Formatter: write directly to the outer writer.
+		p.writer = pp.writer
+	} else {
 		p.writer = &neww
 	}
+	n0 := p.n // Outer writer can already count some bytes.
 	var m int
 	for {
 		start, flags, verb := findVerb(f)
 		p.WriteString(f[:start])
 		if p.err != nil {
-			return p.n, p.err
+			return p.n - n0, p.err
 		}
 		if start == len(f) {
 			break
@@ -55,12 +59,12 @@ func Fprintf(w io.Writer, f string, a ...interface{}) (int, error) {
 			m++
 		}
 		if p.err != nil {
-			return p.n, p.err
+			return p.n - n0, p.err
 		}
 		if m > len(a) {
 			p.fmtErr(verb, "MISSING", nil)
 			if p.err != nil {
-				return p.n, p.err
+				return p.n - n0, p.err
 			}
 		}
 		f = f[start+2+len(flags):]
@@ -71,5 +75,5 @@ func Fprintf(w io.Writer, f string, a ...interface{}) (int, error) {
 			break
 		}
 	}
-	return p.n, p.err
+	return p.n - n0, p.err
 }
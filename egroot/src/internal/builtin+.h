@@ -18,6 +18,30 @@ void panicIC();
 	(slice){internal$Alloc(c, sizeof(typ), __alignof__(typ)), l, c}; \
 })
 
+#define APPEND(typ, slx, addx) ({                                            \
+	slice s = slx;                                                           \
+	slice a = addx;                                                          \
+	uintptr n = s.len + a.len;                                               \
+	if (n > s.cap) {                                                         \
+		uintptr c = s.cap * 2;                                               \
+		if (c < n) {                                                         \
+			c = n;                                                           \
+		}                                                                    \
+		unsafe$Pointer p = internal$Alloc(c, sizeof(typ), __alignof__(typ)); \
+		memmove(p, s.arr, s.len * sizeof(typ));                              \
+		s.arr = p;                                                           \
+		s.cap = c;                                                           \
+	}                                                                        \
+	memmove((byte*)s.arr + s.len * sizeof(typ), a.arr, a.len * sizeof(typ)); \
+	s.len = n;                                                               \
+	s;                                                                       \
+})
+
+#define APPENDSTR(slx, strx) ({                              \
+	string str = strx;                                       \
+	APPEND(byte, slx, ((slice){str.str, str.len, str.len})); \
+})
+
 #define NEWSTR(bx) ({                                        \
 	slice b = bx;                                            \
 	string s = (string){internal$Alloc(b.len, 1, 1), b.len}; \
@@ -25,6 +49,11 @@ void panicIC();
 	s;                                                       \
 })
 
+#define CLEAR(typ, slx) ({                 \
+	slice s = slx;                         \
+	memset(s.arr, 0, s.len * sizeof(typ)); \
+})
+
 #define EQUALA(a1, a2) \
 	(internal$Memcmp((a1).arr, (a2).arr, sizeof((a1).arr)) == 0)
 
@@ -43,4 +72,34 @@ cmpstr(string s1, string s2) {
 		return -1;
 	}
 	return 1;
-}
\ No newline at end of file
+}
+
+#define MINMAX(typ, sel, ...) ({                                \
+	typ _a[] = {__VA_ARGS__};                                   \
+	typ _m = _a[0];                                             \
+	for (uintptr _i = 1; _i < sizeof(_a) / sizeof(typ); ++_i) { \
+		typ _v = _a[_i];                                        \
+		if (sel) {                                              \
+			_m = _v;                                            \
+		}                                                       \
+	}                                                           \
+	_m;                                                         \
+})
+
+#define MIN(typ, ...) MINMAX(typ, _v < _m, __VA_ARGS__)
+#define MAX(typ, ...) MINMAX(typ, _v > _m, __VA_ARGS__)
+
+// Any NaN argument gives NaN result. Negative zero is less than positive zero.
+#define MINF(typ, ...) MINMAX(                                  \
+	typ,                                                        \
+	_v < _m || _v != _v || (_v == _m && __builtin_signbit(_v)), \
+	__VA_ARGS__                                                 \
+)
+#define MAXF(typ, ...) MINMAX(                                   \
+	typ,                                                         \
+	_v > _m || _v != _v || (_v == _m && !__builtin_signbit(_v)), \
+	__VA_ARGS__                                                  \
+)
+
+#define MINSTR(...) MINMAX(string, cmpstr(_v, _m) < 0, __VA_ARGS__)
+#define MAXSTR(...) MINMAX(string, cmpstr(_v, _m) > 0, __VA_ARGS__)
@@ -113,10 +113,16 @@ void close(chan c) {
 
 inline __attribute__((always_inline))
 int_ clen(chan c) {
+	if (c == nil) {
+		return 0;
+	}
 	return c->M->Len(c->C);
 }
 
 inline __attribute__((always_inline))
 int_ ccap(chan c) {
+	if (c == nil) {
+		return 0;
+	}
 	return c->M->Cap(c->C);
 }
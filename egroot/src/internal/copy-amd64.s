@@ -17,8 +17,9 @@ memcpy:
 	je   1f
 
 	mov  %rdx, %rcx
+	// Forward copy if dst <= src.
 	cmp  %rsi, %rdi
-	jg   0f
+	jbe  0f
 
 	// Backward copy:
 	dec  %rdx
@@ -1,3 +1,50 @@
 package internal
 
-var Panic func(i interface{})
\ No newline at end of file
+import "unsafe"
+
+var Panic func(i interface{})
+
+type stringer interface {
+	String() string
+}
+
+// PanicText returns text description of panic argument i. Integer value is
+// formatted into buf, so PanicText does not allocate.
+func PanicText(buf []byte, i interface{}) string {
+	switch v := i.(type) {
+	case nil:
+		return "panic called with nil argument"
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case stringer:
+		return v.String()
+	case int:
+		b := itoa(buf, v)
+		return *(*string)(unsafe.Pointer(&b))
+	}
+	return "<no text descr>"
+}
+
+// itoa formats v into the end of buf without any allocation.
+func itoa(buf []byte, v int) []byte {
+	u := uint(v)
+	if v < 0 {
+		u = uint(-v)
+	}
+	n := len(buf)
+	for {
+		n--
+		buf[n] = byte('0' + u%10)
+		u /= 10
+		if u == 0 {
+			break
+		}
+	}
+	if v < 0 {
+		n--
+		buf[n] = '-'
+	}
+	return buf[n:]
+}
@@ -1,5 +1,9 @@
 package math
 
+const (
+	Pi = 3.14159265358979323846264338327950288419716939937510582097494459
+)
+
 const (
 	MaxFloat32 = 3.40282346638528859811704183484516925440e+38
 	MaxFloat64 = 1.797693134862315708145274237317043567981e+308
@@ -0,0 +1,75 @@
+package math
+
+import (
+	"testing"
+)
+
+// Expected values were calculated on host using Go standard math package.
+
+var sqrtTests = []struct{ x, y float64 }{
+	{0, 0},
+	{5e-324, 2.2227587494850775e-162},
+	{1e-300, 1e-150},
+	{0.25, 0.5},
+	{0.5, 0.7071067811865476},
+	{1, 1},
+	{2, 1.4142135623730951},
+	{3, 1.7320508075688772},
+	{3.141592653589793, 1.7724538509055159},
+	{12345.678, 111.11110655555547},
+	{1e+300, 1e+150},
+}
+
+var sinCosTests = []struct{ x, sin, cos float64 }{
+	{-10, 0.5440211108893699, -0.8390715290764524},
+	{-1, -0.8414709848078965, 0.5403023058681398},
+	{0, 0, 1},
+	{0.5, 0.479425538604203, 0.8775825618903728},
+	{1, 0.8414709848078965, 0.5403023058681398},
+	{1.5707963267948966, 1, 6.123233995736757e-17},
+	{3, 0.1411200080598672, -0.9899924966004454},
+	{10, -0.5440211108893699, -0.8390715290764524},
+	{100, -0.5063656411097588, 0.8623188722876839},
+	{100000, 0.03574879797201651, -0.9993608074382125},
+}
+
+func near(a, b, tol float64) bool {
+	return Abs(a-b) <= tol
+}
+
+func TestSqrt(t *testing.T) {
+	for _, tt := range sqrtTests {
+		if y := softSqrt(tt.x); !near(y, tt.y, tt.y*1e-15) {
+			t.Errorf("softSqrt(%g) = %g, want %g", tt.x, y, tt.y)
+		}
+		if y := Sqrt(tt.x); !near(y, tt.y, tt.y*1e-15) {
+			t.Errorf("Sqrt(%g) = %g, want %g", tt.x, y, tt.y)
+		}
+		if tt.x > 1e38 || tt.x < 1e-38 && tt.x != 0 {
+			continue // Out of float32 normal range.
+		}
+		y32 := float64(Sqrt32(float32(tt.x)))
+		if !near(y32, tt.y, tt.y*1e-7) {
+			t.Errorf("Sqrt32(%g) = %g, want %g", tt.x, y32, tt.y)
+		}
+	}
+	for _, x := range []float64{-1, NaN()} {
+		if y := softSqrt(x); !IsNaN(y) {
+			t.Errorf("softSqrt(%g) = %g, want NaN", x, y)
+		}
+	}
+	if y := softSqrt(Inf(1)); !IsInf(y, 1) {
+		t.Errorf("softSqrt(+Inf) = %g, want +Inf", y)
+	}
+}
+
+func TestSinCos(t *testing.T) {
+	for _, tt := range sinCosTests {
+		if y := Sin(tt.x); !near(y, tt.sin, 1e-15) {
+			t.Errorf("Sin(%g) = %g, want %g", tt.x, y, tt.sin)
+		}
+		if y := Cos(tt.x); !near(y, tt.cos, 1e-15) {
+			t.Errorf("Cos(%g) = %g, want %g", tt.x, y, tt.cos)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// +build cortexm7d
+
+inline __attribute__((always_inline))
+float64
+math$sqrt(float64 x) {
+	float64 y;
+	asm ("vsqrt.f64 %P0, %P1":"=w" (y):"w"(x));
+	return y;
+}
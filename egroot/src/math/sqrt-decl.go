@@ -0,0 +1,6 @@
+// +build cortexm7d
+
+package math
+
+//c:inline
+func sqrt(x float64) float64
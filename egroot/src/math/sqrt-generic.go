@@ -0,0 +1,7 @@
+// +build !cortexm7d
+
+package math
+
+func sqrt(x float64) float64 {
+	return softSqrt(x)
+}
@@ -0,0 +1,9 @@
+// +build cortexm4f cortexm7f cortexm7d
+
+inline __attribute__((always_inline))
+float32
+math$sqrt32(float32 x) {
+	float32 y;
+	asm ("vsqrt.f32 %0, %1":"=t" (y):"t"(x));
+	return y;
+}
@@ -0,0 +1,6 @@
+// +build cortexm4f cortexm7f cortexm7d
+
+package math
+
+//c:inline
+func sqrt32(x float32) float32
@@ -0,0 +1,8 @@
+// +build !cortexm4f,!cortexm7f,!cortexm7d
+
+package math
+
+func sqrt32(x float32) float32 {
+	// Double precision square root rounded to float32 is correctly rounded.
+	return float32(sqrt(float64(x)))
+}
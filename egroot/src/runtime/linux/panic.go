@@ -1,6 +1,15 @@
 package linux
 
+import (
+	"internal"
+	"syscall"
+)
+
 func panic_(i interface{}) {
+	var buf [20]byte
+	syscall.WriteString(2, "\npanic: ")
+	syscall.WriteString(2, internal.PanicText(buf[:], i))
+	syscall.WriteString(2, "\n")
 	for {
 	}
 }
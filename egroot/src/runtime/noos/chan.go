@@ -36,6 +36,7 @@ func makeChan(cap int, size, align uintptr) *internal.Chan {
 type waiter struct {
 	addr unsafe.Pointer
 	next *waiter
+	list **waiter // List of waiters to which the waiter was added.
 }
 
 func shuffle(comms []*internal.Comm) {
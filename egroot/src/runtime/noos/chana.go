@@ -183,6 +183,8 @@ func (c *chanA) Done(n uintptr) {
 	c.event.Send()
 }
 
+// Len returns the number of elements in the channel buffer. It includes the
+// elements of sends that have reserved a slot but haven't called Done yet.
 func (c *chanA) Len() int {
 	torecv := atomic.LoadUintptr(&c.torecv)
 	tosend := atomic.LoadUintptr(&c.tosend)
@@ -16,6 +16,7 @@ func atomicLoadWaiter(wptr **waiter) *waiter {
 }
 
 func addWaiter(head **waiter, w *waiter) {
+	w.list = head
 	w.next = nil
 	if *head == nil {
 		*head = w
@@ -109,6 +110,10 @@ func (c *chanS) TrySend(e unsafe.Pointer, w *waiter) (p unsafe.Pointer, d uintpt
 	} else {
 		if w.next != w {
 			// Altready waiting for communication.
+			if w.list != &c.src {
+				// Waiting in another list (select).
+				return nil, cagain
+			}
 			if atomic.LoadPointer(&w.addr) == nil {
 				// Receiver is ready.
 				atomic.StorePointer(&w.addr, e)
@@ -183,6 +188,10 @@ func (c *chanS) TryRecv(e unsafe.Pointer, w *waiter) (p unsafe.Pointer, d uintpt
 	} else {
 		if w.next != w {
 			// Altready waiting for communication.
+			if w.list != &c.dst {
+				// Waiting in another list (select).
+				return nil, cagain
+			}
 			if atomic.LoadPointer(&w.addr) == nil {
 				// Sender is ready.
 				atomic.StorePointer(&w.addr, e)
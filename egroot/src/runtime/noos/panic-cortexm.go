@@ -3,28 +3,16 @@
 package noos
 
 import (
+	"internal"
+
 	"arch/cortexm/debug/itm"
 )
 
-type stringer interface {
-	String() string
-}
-
 func panic_(i interface{}) {
-	var s string
-	switch v := i.(type) {
-	case string:
-		s = v
-	case error:
-		s = v.Error()
-	case stringer:
-		s = v.String()
-	default:
-		s = "<no text descr>"
-	}
+	var buf [20]byte
 	dbg := itm.Port(0)
 	dbg.WriteString("\npanic: ")
-	dbg.WriteString(s)
+	dbg.WriteString(internal.PanicText(buf[:], i))
 	dbg.WriteByte('\n')
 	for {
 	}
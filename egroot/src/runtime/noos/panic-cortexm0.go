@@ -2,20 +2,11 @@
 
 package noos
 
-type stringer interface {
-	String() string
-}
+import "internal"
 
 func panic_(i interface{}) {
-	var s string
-	switch v := i.(type) {
-	case string:
-		s = v
-	case error:
-		s = v.Error()
-	case stringer:
-		s = v.String()
-	}
+	var buf [20]byte
+	s := internal.PanicText(buf[:], i)
 	_ = s
 	for {
 	}
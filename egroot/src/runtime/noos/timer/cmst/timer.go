@@ -60,7 +60,8 @@ func Setup(periodns, hz uint, external bool) {
 // SetWakeup: see syscall.SetSysTimer.
 func SetWakeup(ns int64) {}
 
-// Nanosec: see syscall.SetSysClock.
+// Nanosec: see syscall.SetSysClock. It can be called from any ISR, also from
+// that with priority higher than SysTick exception.
 func Nanosec() int64 {
 	if g.freqHz == 0 {
 		return 0
@@ -68,7 +69,15 @@ func Nanosec() int64 {
 	aba := g.counter.ABA()
 	for {
 		cnt := g.counter.TryLoad(aba)
-		add := g.periodTicks - uint32(systick.SYSTICK.CURRENT().Load())
+		cur := systick.SYSTICK.CURRENT().Load()
+		if scb.SCB.PENDSTSET().Load() != 0 {
+			// SysTick has wrapped but sysTickHandler has not been run yet
+			// (Nanosec called from higher priority ISR). Read CURRENT again
+			// to be sure that it was loaded from RELOAD.
+			cur = systick.SYSTICK.CURRENT().Load()
+			cnt += int64(g.periodTicks)
+		}
+		add := g.periodTicks - uint32(cur)
 		var ok bool
 		if aba, ok = g.counter.CheckABA(aba); ok {
 			return ticktons(cnt + int64(add))
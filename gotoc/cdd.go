@@ -54,6 +54,9 @@ type CDD struct {
 	where       where
 	constInit   bool
 	dfsm        int8
+	dfr         *results            // results of function that contains defer
+	roparams    map[*types.Var]bool // parameters marked by emgo:const
+	fvars       []string            // declarations of function scope variables
 
 	acds []*CDD // additional CDDs
 }
@@ -1,11 +1,15 @@
 package gotoc
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"io"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -20,14 +24,17 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 	w := new(bytes.Buffer)
 
 	pragmas, cattrs := gtc.pragmas(d)
+	var roparams []string
 	for _, p := range pragmas {
-		switch p {
-		case "inline":
+		switch {
+		case p == "inline":
 			cdd.Complexity -= cdd.gtc.noinlineThres
-		case "noinline":
+		case p == "noinline":
 			cdd.Complexity += cdd.gtc.noinlineThres
-		case "export":
+		case p == "export":
 			cdd.forceExport = true
+		case strings.HasPrefix(p, "const "):
+			roparams = append(roparams, strings.Fields(p[6:])...)
 		}
 	}
 	for _, cattr := range cattrs {
@@ -37,6 +44,9 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 	}
 
 	res, params := cdd.signature(sig, true, orgNames)
+	if len(roparams) > 0 {
+		cdd.constParams(d.Pos(), sig, params, roparams)
+	}
 
 	w.WriteString(res.typ)
 	w.WriteByte(' ')
@@ -56,7 +66,6 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 
 	w.WriteByte(' ')
 
-	all := true
 	if res.hasNames {
 		cdd.indent(w)
 		w.WriteString("{\n")
@@ -64,7 +73,6 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 		for i, v := range res.fields {
 			name := res.names[i]
 			if name == "_" && len(res.fields) > 1 {
-				all = false
 				continue
 			}
 			cdd.indent(w)
@@ -79,7 +87,25 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 		cdd.indent(w)
 	}
 
-	end := cdd.BlockStmt(w, d.Body, res.typ, sig.Results())
+	var end bool
+	m := w.Len()
+	if hasDefer(d.Body) {
+		cdd.deferBlock(w, d.Body, &res, sig.Results())
+	} else {
+		end = cdd.BlockStmt(w, d.Body, res.typ, sig.Results())
+	}
+	if len(cdd.fvars) > 0 {
+		// Insert function scope variables after opening brace of body.
+		body := append([]byte(nil), w.Bytes()[m+2:]...)
+		w.Truncate(m + 2)
+		cdd.il++
+		for _, v := range cdd.fvars {
+			cdd.indent(w)
+			w.WriteString(v + ";\n")
+		}
+		cdd.il--
+		w.Write(body)
+	}
 	w.WriteByte('\n')
 
 	if res.hasNames {
@@ -90,29 +116,7 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 			cdd.il++
 
 			cdd.indent(w)
-			w.WriteString("return ")
-			if len(res.fields) == 1 {
-				w.WriteString(res.names[0])
-			} else {
-				w.WriteString("(" + res.typ + "){")
-				comma := false
-				for i, name := range res.names {
-					if name == "_" {
-						continue
-					}
-					if comma {
-						w.WriteString(", ")
-					} else {
-						comma = true
-					}
-					if !all {
-						w.WriteString("._" + strconv.Itoa(i) + "=")
-					}
-					w.WriteString(name)
-				}
-				w.WriteByte('}')
-			}
-			w.WriteString(";\n")
+			w.WriteString("return " + res.namedStr() + ";\n")
 		}
 		cdd.il--
 		w.WriteString("}\n")
@@ -125,6 +129,38 @@ func (gtc *GTC) FuncDecl(d *ast.FuncDecl, il int) (cdds []*CDD) {
 	return
 }
 
+// constParams marks parameters listed in emgo:const pragma as read-only.
+// Pointer parameter is declared as pointer to const. Slice parameter has
+// unchanged type but its elements are indexed using pointer to const, so only
+// assignments to indexed elements are detected by C compiler. Writes by copy,
+// clear or by other function that receives the slice (or a pointer to its
+// element) are not detected.
+func (cdd *CDD) constParams(pos token.Pos, sig *types.Signature, prms params, names []string) {
+	ps := sig.Params()
+	off := len(prms) - ps.Len() // Skip receiver.
+	for _, name := range names {
+		i := 0
+		for i < ps.Len() && ps.At(i).Name() != name {
+			i++
+		}
+		if i == ps.Len() {
+			cdd.exit(pos, "emgo:const: %s isn't function parameter", name)
+		}
+		v := ps.At(i)
+		switch v.Type().Underlying().(type) {
+		case *types.Pointer:
+			prms[off+i].typ = "const " + prms[off+i].typ
+		case *types.Slice:
+		default:
+			cdd.exit(pos, "emgo:const requires parameter of pointer or slice type")
+		}
+		if cdd.roparams == nil {
+			cdd.roparams = make(map[*types.Var]bool)
+		}
+		cdd.roparams[v] = true
+	}
+}
+
 func (gtc *GTC) GenDecl(d *ast.GenDecl, il int) (cdds []*CDD) {
 	w := new(bytes.Buffer)
 
@@ -159,7 +195,7 @@ func (gtc *GTC) GenDecl(d *ast.GenDecl, il int) (cdds []*CDD) {
 				w.WriteString("#define ")
 				cdd.Name(w, c, true)
 				w.WriteByte(' ')
-				cdd.Value(w, c.Val(), c.Type())
+				cdd.Value(w, c.Val(), c.Type(), n.Pos())
 				cdd.copyDecl(w, "\n")
 				w.Reset()
 
@@ -173,15 +209,23 @@ func (gtc *GTC) GenDecl(d *ast.GenDecl, il int) (cdds []*CDD) {
 			vs := s.(*ast.ValueSpec)
 			vals := vs.Values
 			pragmas, cattrs := gtc.pragmas(d, vs)
-			var pconst, pexport bool
+			var (
+				pconst, pexport bool
+				pembed          string
+			)
 			for _, p := range pragmas {
-				switch p {
-				case "export":
+				switch {
+				case p == "export":
 					pexport = true
-				case "const":
+				case p == "const":
 					pconst = true
+				case strings.HasPrefix(p, "embed "):
+					pembed = strings.TrimSpace(p[6:])
 				}
 			}
+			if pembed != "" && (len(vs.Names) != 1 || len(vals) != 0) {
+				gtc.exit(vs.Pos(), "emgo:embed requires single variable without value")
+			}
 			cattr := strings.Join(cattrs, " ")
 			for i, n := range vs.Names {
 				v := gtc.object(n).(*types.Var)
@@ -200,7 +244,11 @@ func (gtc *GTC) GenDecl(d *ast.GenDecl, il int) (cdds []*CDD) {
 				} else {
 					indent = true
 				}
-				cdd.varDecl(w, v.Type(), name, val, cattr, pconst, true)
+				if pembed != "" {
+					cdd.embedDecl(w, v, name, pembed, cattr, pconst)
+				} else {
+					cdd.varDecl(w, v.Type(), name, val, cattr, pconst, true)
+				}
 				w.Reset()
 				cdds = append(cdds, cdd)
 			}
@@ -335,6 +383,106 @@ func (cdd *CDD) varDecl(w *bytes.Buffer, typ types.Type, name string, val ast.Ex
 	}
 }
 
+// embedDecl declares global variable of type string or []byte initialized
+// with the content of file. Relative path is relative to the directory of the
+// source file. The content is emitted as C string literal, so it is placed in
+// read-only memory (Flash) even if v isn't constant. The file is read using
+// buffered reader but the whole escaped content is held in memory (in Def of
+// cdd) until the C file is written, like any other definition.
+func (cdd *CDD) embedDecl(w *bytes.Buffer, v *types.Var, name, path, cattrs string, pconst bool) {
+	var isstr, ok bool
+	switch t := v.Type().Underlying().(type) {
+	case *types.Basic:
+		isstr = t.Kind() == types.String
+		ok = isstr
+	case *types.Slice:
+		e, isb := t.Elem().Underlying().(*types.Basic)
+		ok = isb && e.Kind() == types.Byte
+	}
+	if !ok || !cdd.gtc.isGlobal(v) {
+		cdd.exit(v.Pos(), "emgo:embed requires global variable of type string or []byte")
+	}
+	if !filepath.IsAbs(path) {
+		dir := filepath.Dir(cdd.gtc.fset.Position(v.Pos()).Filename)
+		path = filepath.Join(dir, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		cdd.exit(v.Pos(), "emgo:embed: %v", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		cdd.exit(v.Pos(), "emgo:embed: %v", err)
+	}
+
+	if cattrs != "" {
+		w.WriteString(cattrs)
+		w.WriteByte(' ')
+	}
+	cdd.Type(w, v.Type())
+	w.WriteByte(' ')
+	if pconst {
+		w.WriteString("const ")
+	}
+	w.WriteString(name)
+	cdd.copyDecl(w, ";\n")
+	w.Reset()
+	cdd.constInit = true
+	cdd.indent(w)
+	w.WriteString("__typeof__(" + name + ") " + name + " = ")
+	if isstr {
+		w.WriteString("EGSTR(")
+	} else {
+		w.WriteString("CSLICE(" + strconv.FormatInt(fi.Size(), 10) + ", (byte*)")
+	}
+	if err := cdd.cstring(w, bufio.NewReader(f)); err != nil {
+		cdd.exit(v.Pos(), "emgo:embed: %v", err)
+	}
+	w.WriteString(");\n")
+	cdd.copyDef(w)
+}
+
+// cstring writes content of r as C string literal, split into lines.
+// Non-printable characters are written as octal escapes that, unlike
+// hexadecimal ones, can not consume following characters.
+func (cdd *CDD) cstring(w *bytes.Buffer, r io.ByteReader) error {
+	const lineLen = 32
+	w.WriteString("\n")
+	cdd.il++
+	cdd.indent(w)
+	w.WriteByte('"')
+	for n := 0; ; n++ {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 && n%lineLen == 0 {
+			w.WriteString("\"\n")
+			cdd.indent(w)
+			w.WriteByte('"')
+		}
+		switch {
+		case c == '"' || c == '\\' || c == '?':
+			w.WriteByte('\\')
+			w.WriteByte(c)
+		case c >= ' ' && c <= '~':
+			w.WriteByte(c)
+		default:
+			w.WriteByte('\\')
+			w.WriteByte('0' + c>>6)
+			w.WriteByte('0' + c>>3&7)
+			w.WriteByte('0' + c&7)
+		}
+	}
+	w.WriteByte('"')
+	cdd.il--
+	return nil
+}
+
 // isConstExpr returns true if val can be represented as C constant expr.
 // typ is destination type.
 func (cdd *CDD) isConstExpr(val ast.Expr, typ types.Type) bool {
@@ -501,9 +649,12 @@ func (cdd *CDD) structDecl(w *bytes.Buffer, name string, typ types.Type, cattrs
 	w.WriteString("struct ")
 	w.WriteString(name)
 	w.WriteByte('_')
-	if it, ok := typ.(*types.Interface); ok {
-		cdd.iface(w, it)
-	} else {
+	switch t := typ.(type) {
+	case *types.Interface:
+		cdd.iface(w, t)
+	case *types.Struct:
+		cdd.structBody(w, t)
+	default:
 		cdd.Type(w, typ)
 	}
 	w.WriteString(";\n")
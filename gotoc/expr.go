@@ -47,18 +47,68 @@ func writeInt(w *bytes.Buffer, ev constant.Value, k types.BasicKind, sizInt int6
 	}
 }
 
-func writeFloat(w *bytes.Buffer, ev constant.Value, k types.BasicKind) {
+func floatStr(ev constant.Value, k types.BasicKind) string {
+	var s string
 	if k == types.Float32 {
 		f, _ := constant.Float32Val(ev)
-		w.WriteString(strconv.FormatFloat(float64(f), 'e', -1, 32))
-		w.WriteByte('F')
+		s = strconv.FormatFloat(float64(f), 'e', -1, 32) + "F"
 	} else {
 		f, _ := constant.Float64Val(ev)
-		w.WriteString(strconv.FormatFloat(f, 'e', -1, 64))
+		s = strconv.FormatFloat(f, 'e', -1, 64)
+	}
+	if strings.HasPrefix(s, "-0e") {
+		// Go constants have no negative zero (tiny negative value can be
+		// rounded to it).
+		s = s[1:]
+	}
+	return s
+}
+
+func writeFloat(w *bytes.Buffer, ev constant.Value, k types.BasicKind) {
+	s := floatStr(ev, k)
+	if s[0] == '-' {
+		w.WriteString("(" + s + ")")
+	} else {
+		w.WriteString(s)
 	}
 }
 
-func (cdd *CDD) Value(w *bytes.Buffer, ev constant.Value, t types.Type) {
+// intRange returns the minimum and maximum value of integer kind k.
+func (gtc *GTC) intRange(k types.BasicKind) (min, max constant.Value) {
+	var size int64
+	switch k {
+	case types.Int8, types.Uint8:
+		size = 1
+	case types.Int16, types.Uint16:
+		size = 2
+	case types.Int32, types.Uint32:
+		size = 4
+	case types.Int64, types.Uint64:
+		size = 8
+	case types.Int, types.Uint:
+		size = gtc.sizInt
+	case types.Uintptr:
+		size = gtc.sizPtr
+	}
+	bits := uint(size * 8)
+	one := constant.MakeInt64(1)
+	if k >= types.Uint && k <= types.Uintptr {
+		min = constant.MakeInt64(0)
+		max = constant.BinaryOp(
+			constant.Shift(one, token.SHL, bits), token.SUB, one,
+		)
+		return
+	}
+	min = constant.UnaryOp(
+		token.SUB, constant.Shift(one, token.SHL, bits-1), 0,
+	)
+	max = constant.BinaryOp(
+		constant.Shift(one, token.SHL, bits-1), token.SUB, one,
+	)
+	return
+}
+
+func (cdd *CDD) Value(w *bytes.Buffer, ev constant.Value, t types.Type, pos token.Pos) {
 	if o, ok := t.(*types.Named); ok {
 		cdd.addObject(o.Obj(), false)
 	}
@@ -67,18 +117,24 @@ func (cdd *CDD) Value(w *bytes.Buffer, ev constant.Value, t types.Type) {
 	case k <= types.Bool || k == types.UntypedBool:
 		w.WriteString(ev.String())
 	case k <= types.Uintptr || k == types.UntypedInt || k == types.UntypedRune:
+		if k <= types.Uintptr {
+			min, max := cdd.gtc.intRange(k)
+			if constant.Compare(ev, token.LSS, min) ||
+				constant.Compare(ev, token.GTR, max) {
+				cdd.exit(pos, "constant %v overflows %v", ev, t)
+			}
+		}
 		writeInt(w, ev, k, cdd.gtc.sizInt)
 	case k <= types.Float64 || k == types.UntypedFloat:
 		writeFloat(w, ev, k)
 	case k <= types.Complex128 || k == types.UntypedComplex:
 		w.WriteByte('(')
-		writeFloat(w, constant.Real(ev), k)
-		im := constant.Imag(ev)
-		if constant.Sign(im) != -1 {
+		w.WriteString(floatStr(constant.Real(ev), k))
+		im := floatStr(constant.Imag(ev), k)
+		if im[0] != '-' {
 			w.WriteByte('+')
 		}
-		writeFloat(w, im, k)
-		w.WriteString("i)")
+		w.WriteString(im + "i)")
 	case k == types.String || k == types.UntypedString:
 		if cdd.constInit {
 			w.WriteString("EGSTR(")
@@ -236,7 +292,25 @@ func (cdd *CDD) SelectorExpr(w *bytes.Buffer, e *ast.SelectorExpr, permitaa bool
 		}
 
 	case types.MethodExpr:
+		rtyp := sel.Obj().Type().(*types.Signature).Recv().Type()
+		_, ptrRecv := rtyp.(*types.Pointer)
+		if _, ok := sel.Recv().(*types.Pointer); !ok || ptrRecv {
+			cdd.Name(w, sel.Obj(), true)
+			break
+		}
+		// (*T).Method where Method has non-pointer receiver: receiver must be
+		// dereferenced.
+		sig := cdd.exprType(e).(*types.Signature)
+		res, params := cdd.signature(sig, false, numNames)
+		w.WriteString("({" + res.typ + " ")
+		w.WriteString(dimFuncPtr("func"+params.String(), res.dim))
+		w.WriteString(" { return ")
 		cdd.Name(w, sel.Obj(), true)
+		w.WriteString("(*_1")
+		for i := 2; i <= sig.Params().Len(); i++ {
+			w.WriteString(", _" + strconv.Itoa(i))
+		}
+		w.WriteString("); } func;})")
 
 	default:
 		cdd.notImplemented(e)
@@ -251,8 +325,9 @@ func (cdd *CDD) SelectorExprStr(e *ast.SelectorExpr, permitaa bool) (s string, f
 	return
 }
 
-func (cdd *CDD) builtin(b *types.Builtin, args []ast.Expr) (fun, recv string) {
+func (cdd *CDD) builtin(b *types.Builtin, e *ast.CallExpr) (fun, recv string) {
 	name := b.Name()
+	args := e.Args
 
 	switch name {
 	case "len":
@@ -299,6 +374,20 @@ func (cdd *CDD) builtin(b *types.Builtin, args []ast.Expr) (fun, recv string) {
 			panic(t)
 		}
 
+	case "append":
+		et := cdd.exprType(args[0]).Underlying().(*types.Slice).Elem()
+		if e.Ellipsis.IsValid() {
+			t, _ := cdd.exprType(args[1]).Underlying().(*types.Basic)
+			b, _ := et.Underlying().(*types.Basic)
+			if t != nil && t.Info()&types.IsString != 0 &&
+				b != nil && b.Kind() == types.Byte {
+				// append([]byte, string...)
+				return "APPENDSTR", ""
+			}
+		}
+		typ, dim := cdd.TypeStr(et)
+		return "APPEND", typ + dimFuncPtr("", dim)
+
 	case "new":
 		typ, dim := cdd.TypeStr(cdd.exprType(args[0]))
 		args[0] = nil
@@ -340,12 +429,37 @@ func (cdd *CDD) builtin(b *types.Builtin, args []ast.Expr) (fun, recv string) {
 			cdd.notImplemented(ast.NewIdent(name))
 		}
 
+	case "min", "max":
+		fun = strings.ToUpper(name)
+		rt := cdd.exprType(e)
+		t := rt.Underlying().(*types.Basic)
+		switch {
+		case t.Info()&types.IsString != 0:
+			return fun + "STR", ""
+		case t.Info()&types.IsFloat != 0:
+			fun += "F"
+		}
+		typ, _ := cdd.TypeStr(rt)
+		return fun, typ
+
+	case "clear":
+		switch t := cdd.exprType(args[0]).Underlying().(type) {
+		case *types.Slice:
+			typ, dim := cdd.TypeStr(t.Elem())
+			return "CLEAR", typ + dimFuncPtr("", dim)
+
+		default:
+			// There is no runtime map implementation.
+			cdd.notImplemented(ast.NewIdent("clear"), t)
+		}
+
 	}
 
 	return name, ""
 }
 
-func (cdd *CDD) funStr(fe ast.Expr, args []ast.Expr) (fs string, ft types.Type, rs string, rt types.Type) {
+func (cdd *CDD) funStr(e *ast.CallExpr) (fs string, ft types.Type, rs string, rt types.Type) {
+	fe := e.Fun
 	switch f := fe.(type) {
 	case *ast.SelectorExpr:
 		buf := new(bytes.Buffer)
@@ -356,7 +470,7 @@ func (cdd *CDD) funStr(fe ast.Expr, args []ast.Expr) (fs string, ft types.Type,
 	case *ast.Ident:
 		switch o := cdd.object(f).(type) {
 		case *types.Builtin:
-			fs, rs = cdd.builtin(o, args)
+			fs, rs = cdd.builtin(o, e)
 
 		default:
 			fs = cdd.NameStr(o, true)
@@ -372,7 +486,7 @@ func (cdd *CDD) funStr(fe ast.Expr, args []ast.Expr) (fs string, ft types.Type,
 func (cdd *CDD) CallExpr(w *bytes.Buffer, e *ast.CallExpr, permitaa bool) {
 	switch t := cdd.exprType(e.Fun).(type) {
 	case *types.Signature:
-		c := cdd.call(e, t, false)
+		c := cdd.call(e, t, false, "")
 		if c.rcv.r != "" || c.arr.r != "" || c.tup.t != nil {
 			w.WriteString("({\n")
 			cdd.il++
@@ -447,6 +561,20 @@ func (cdd *CDD) CallExpr(w *bytes.Buffer, e *ast.CallExpr, permitaa bool) {
 		case *types.Interface:
 			cdd.interfaceExpr(w, arg, t, permitaa)
 
+		case *types.Struct, *types.Array:
+			if cdd.constInit || types.Identical(t, at) {
+				// Composite literal in constant initializer is a brace list.
+				cdd.Expr(w, arg, t, permitaa)
+				break
+			}
+			// C does not allow to cast struct types.
+			w.WriteString("CONVERT(")
+			dim := cdd.Type(w, t)
+			w.WriteString(dimFuncPtr("", dim))
+			w.WriteString(", ")
+			cdd.Expr(w, arg, t, permitaa)
+			w.WriteByte(')')
+
 		default:
 			if b, ok := typ.(*types.Basic); ok && b.Kind() == types.String {
 				if _, ok := at.(*types.Slice); ok {
@@ -488,7 +616,7 @@ func (cdd *CDD) CallExpr(w *bytes.Buffer, e *ast.CallExpr, permitaa bool) {
 func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa bool) {
 	if t := cdd.gtc.ti.Types[expr]; t.Value != nil {
 		// Constant expression
-		cdd.Value(w, t.Value, t.Type)
+		cdd.Value(w, t.Value, t.Type, expr.Pos())
 		return
 	}
 
@@ -500,8 +628,14 @@ func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa b
 		ltyp := cdd.exprType(e.X)
 		rtyp := cdd.exprType(e.Y)
 
-		lhs := cdd.ExprStr(e.X, ltyp, permitaa)
-		rhs := cdd.ExprStr(e.Y, rtyp, permitaa)
+		var lhs, rhs string
+		if e.Op == token.EQL || e.Op == token.NEQ {
+			lhs, ltyp = cdd.eqOperand(e.X, ltyp, rtyp, permitaa)
+			rhs, rtyp = cdd.eqOperand(e.Y, rtyp, ltyp, permitaa)
+		} else {
+			lhs = cdd.ExprStr(e.X, ltyp, permitaa)
+			rhs = cdd.ExprStr(e.Y, rtyp, permitaa)
+		}
 
 		if t, ok := ltyp.Underlying().(*types.Basic); ok && t.Kind() == types.String {
 			w.WriteString("(cmpstr(" + lhs + ", " + rhs + ") " + op + " 0)")
@@ -511,6 +645,12 @@ func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa b
 			cdd.eq(w, lhs, op, rhs, ltyp, rtyp)
 			break
 		}
+		if bop, n := cdd.bigShift(e.Op, e.Y, ltyp); bop != "" {
+			w.WriteString("((")
+			cdd.Type(w, ltyp)
+			fmt.Fprintf(w, ")(%s %s %d))", lhs, bop, n)
+			break
+		}
 		switch e.Op {
 		case token.AND_NOT:
 			op = "&~"
@@ -580,7 +720,10 @@ func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa b
 		}
 
 	case *ast.IndexExpr:
-		cdd.indexExpr(w, cdd.exprType(e.X), cdd.ExprStr(e.X, nil, false), e.Index, "")
+		cdd.indexExpr(
+			w, cdd.exprType(e.X), cdd.ExprStr(e.X, nil, false), e.Index, "",
+			cdd.isConstParam(e.X),
+		)
 
 	case *ast.KeyValueExpr:
 		kt := cdd.exprType(e.Key)
@@ -620,12 +763,35 @@ func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa b
 			break
 		}
 		sig := fun.(*types.Signature)
+		// Method value: receiver is evaluated once and saved in function
+		// scope variable, so it outlives this expression and the nested
+		// function can be called after it (but only until the enclosing
+		// function returns).
 		w.WriteString("({")
+		rtyp, rdim := cdd.TypeStr(recvt)
+		r := "_r"
+		if cdd.where == inFuncBody {
+			r = "_mvr" + cdd.gtc.uniqueId()
+			cdd.fvars = append(cdd.fvars, rtyp+" "+dimFuncPtr(r, rdim))
+			w.WriteString(r + " = " + recvs + "; ")
+		} else {
+			w.WriteString(rtyp + " " + dimFuncPtr(r, rdim) + " = " + recvs + "; ")
+		}
 		res, params := cdd.signature(sig, false, numNames)
 		w.WriteString(res.typ)
 		w.WriteByte(' ')
 		w.WriteString(dimFuncPtr("func"+params.String(), res.dim))
-		w.WriteString(" { return " + s + "(" + recvs)
+		w.WriteString(" { return ")
+		if _, ok := recvt.Underlying().(*types.Interface); ok {
+			in, ok := recvt.(*types.Named)
+			if !ok {
+				cdd.exit(e.Pos(), "not supported: method value of unnamed interface")
+			}
+			w.WriteString("((" + cdd.NameStr(in.Obj(), false) + "*)" + r + ".itab)->")
+			w.WriteString(s + "(&" + r + ".val")
+		} else {
+			w.WriteString(s + "(" + r)
+		}
 		if p := sig.Params(); p != nil {
 			for i := 1; i <= p.Len(); i++ {
 				w.WriteString(", _" + strconv.Itoa(i))
@@ -641,6 +807,9 @@ func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa b
 		cdd.Expr(w, e.X, nil, permitaa)
 
 	case *ast.TypeAssertExpr:
+		if e.Type == nil {
+			cdd.exit(e.Pos(), "use of .(type) outside type switch")
+		}
 		w.WriteString("({\n")
 		cdd.il++
 		ityp := cdd.exprType(e.X)
@@ -750,11 +919,38 @@ func (cdd *CDD) Expr(w *bytes.Buffer, expr ast.Expr, nilT types.Type, permitaa b
 		w.WriteString("})")
 
 	default:
-		fmt.Fprintf(w, "!%v<%T>!", e, e)
+		cdd.notImplemented(e)
 	}
 	return
 }
 
+// bigShift handles shift (op is SHL, SHR or its assign form) of operand of
+// type t by constant count y that is not less than the width of t. Go defines
+// the result of such shift (0 or -1 for negative signed operand shifted right)
+// but C doesn't. bigShift returns C operator and its right operand that give
+// the Go result ("&", 0 or ">>", width-1) or empty string if y isn't constant
+// or is less than the width of t.
+func (cdd *CDD) bigShift(op token.Token, y ast.Expr, t types.Type) (string, uint64) {
+	cv := cdd.exprValue(y)
+	if cv == nil {
+		return "", 0
+	}
+	n, ok := constant.Uint64Val(constant.ToInt(cv))
+	if !ok {
+		cdd.exit(y.Pos(), "not supported: shift count %v too large", cv)
+	}
+	bits := uint64(cdd.gtc.siz.Sizeof(t)) * 8
+	if n < bits {
+		return "", 0
+	}
+	b, _ := t.Underlying().(*types.Basic)
+	if (op == token.SHR || op == token.SHR_ASSIGN) && b != nil &&
+		b.Info()&types.IsUnsigned == 0 {
+		return ">>", bits - 1
+	}
+	return "&", 0
+}
+
 func (cdd *CDD) newVar(name string, typ types.Type, global bool, val ast.Expr, permitaa bool) {
 	var pos token.Pos
 	if val != nil {
@@ -901,8 +1097,9 @@ func (cdd *CDD) elts(w *bytes.Buffer, elts []ast.Expr, nilT types.Type, st *type
 	}
 }
 
-// indexExpr assumes that if idx == nil then ids is checked index.
-func (cdd *CDD) indexExpr(w *bytes.Buffer, typ types.Type, xs string, idx ast.Expr, ids string) {
+// indexExpr assumes that if idx == nil then ids is checked index. If ro is
+// true slice elements are accessed using pointer to const.
+func (cdd *CDD) indexExpr(w *bytes.Buffer, typ types.Type, xs string, idx ast.Expr, ids string, ro bool) {
 	pt, isPtr := typ.(*types.Pointer)
 	if isPtr {
 		typ = pt.Elem()
@@ -922,6 +1119,9 @@ func (cdd *CDD) indexExpr(w *bytes.Buffer, typ types.Type, xs string, idx ast.Ex
 		} else {
 			w.WriteString("SLIDX(")
 		}
+		if ro {
+			w.WriteString("const ")
+		}
 		dim := cdd.Type(w, t.Elem())
 		dim = append([]string{"*"}, dim...)
 		w.WriteString(dimFuncPtr("", dim))
@@ -938,7 +1138,11 @@ func (cdd *CDD) indexExpr(w *bytes.Buffer, typ types.Type, xs string, idx ast.Ex
 		}
 	case *types.Map:
 		indT = t.Key()
-		cdd.notImplemented(&ast.IndexExpr{}, t)
+		var pos token.Pos
+		if idx != nil {
+			pos = idx.Pos()
+		}
+		cdd.exit(pos, "not implemented: index of %v", typ)
 	default:
 		panic(t)
 	}
@@ -955,10 +1159,20 @@ func (cdd *CDD) indexExpr(w *bytes.Buffer, typ types.Type, xs string, idx ast.Ex
 // indexExprStr assumes that if idx == nil then ids is checked index.
 func (cdd *CDD) indexExprStr(typ types.Type, xs string, idx ast.Expr, ids string) string {
 	buf := new(bytes.Buffer)
-	cdd.indexExpr(buf, typ, xs, idx, ids)
+	cdd.indexExpr(buf, typ, xs, idx, ids, false)
 	return buf.String()
 }
 
+// isConstParam reports whether e is a parameter marked by emgo:const pragma.
+func (cdd *CDD) isConstParam(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	if !ok || cdd.roparams == nil {
+		return false
+	}
+	v, ok := cdd.object(id).(*types.Var)
+	return ok && cdd.roparams[v]
+}
+
 func (cdd *CDD) SliceExpr(w *bytes.Buffer, e *ast.SliceExpr) {
 	sx := cdd.ExprStr(e.X, nil, false)
 
@@ -1078,6 +1292,20 @@ func (cdd *CDD) Nil(w *bytes.Buffer, t types.Type) {
 
 var unil = types.Typ[types.UntypedNil]
 
+// eqOperand returns C code and type of operand e of == or != operator. If e
+// is compared to interface, and it is not an interface nor nil, it is converted
+// to the other operand type.
+func (cdd *CDD) eqOperand(e ast.Expr, etyp, otyp types.Type, permitaa bool) (string, types.Type) {
+	if etyp != unil {
+		_, ei := etyp.Underlying().(*types.Interface)
+		_, oi := otyp.Underlying().(*types.Interface)
+		if oi && !ei {
+			return cdd.interfaceExprStr(e, otyp, permitaa), otyp
+		}
+	}
+	return cdd.ExprStr(e, otyp, permitaa), etyp
+}
+
 func (cdd *CDD) eq(w *bytes.Buffer, lhs, op, rhs string, ltyp, rtyp types.Type) {
 	typ := ltyp
 	if typ == unil {
@@ -1147,7 +1375,52 @@ func (cdd *CDD) eq(w *bytes.Buffer, lhs, op, rhs string, ltyp, rtyp types.Type)
 		if op == "!=" {
 			w.WriteByte('!')
 		}
-		w.WriteString("EQUALA(" + lhs + ", " + rhs + ")")
+		if memComparable(t) {
+			w.WriteString("EQUALA(" + lhs + ", " + rhs + ")")
+			return
+		}
+		// Element-wise comparison.
+		w.WriteString("({\n")
+		cdd.il++
+		cdd.indent(w)
+		cdd.Type(w, ltyp)
+		id := cdd.gtc.uniqueId()
+		lv := "_l" + id
+		rv := "_r" + id
+		ev := "_e" + id
+		iv := "_i" + id
+		w.WriteString(" " + lv + " = " + lhs + "; ")
+		cdd.Type(w, rtyp)
+		w.WriteString(" " + rv + " = " + rhs + ";\n")
+		cdd.indent(w)
+		w.WriteString("bool " + ev + " = true;\n")
+		cdd.indent(w)
+		w.WriteString("for (int_ " + iv + " = 0; " + iv + " < ")
+		w.WriteString(strconv.FormatInt(t.Len(), 10))
+		w.WriteString("; ++" + iv + ") {\n")
+		cdd.il++
+		cdd.indent(w)
+		w.WriteString("if (!")
+		et := t.Elem()
+		idx := ".arr[" + iv + "]"
+		cdd.eq(w, lv+idx, "==", rv+idx, et, et)
+		w.WriteString(") {\n")
+		cdd.il++
+		cdd.indent(w)
+		w.WriteString(ev + " = false;\n")
+		cdd.indent(w)
+		w.WriteString("break;\n")
+		cdd.il--
+		cdd.indent(w)
+		w.WriteString("}\n")
+		cdd.il--
+		cdd.indent(w)
+		w.WriteString("}\n")
+		cdd.indent(w)
+		w.WriteString(ev + ";\n")
+		cdd.il--
+		cdd.indent(w)
+		w.WriteString("})")
 		return
 	case *types.Slice:
 		nilv := "nil"
@@ -1163,6 +1436,19 @@ func (cdd *CDD) eq(w *bytes.Buffer, lhs, op, rhs string, ltyp, rtyp types.Type)
 	w.WriteString("(" + lhs + " " + op + " " + rhs + ")")
 }
 
+// memComparable reports whether values of type t can be compared using memcmp.
+func memComparable(t types.Type) bool {
+	switch t := t.Underlying().(type) {
+	case *types.Basic:
+		return t.Info()&(types.IsString|types.IsFloat|types.IsComplex) == 0
+	case *types.Pointer, *types.Chan:
+		return true
+	case *types.Array:
+		return memComparable(t.Elem())
+	}
+	return false
+}
+
 func (cdd *CDD) interfaceES(w *bytes.Buffer, ex ast.Expr, es string, epos token.Pos, etyp, ityp types.Type, permitaa bool) {
 	simple := ityp == nil || etyp == nil || types.Identical(ityp, etyp)
 	if !simple {
@@ -10,6 +10,7 @@ import (
 	"go/token"
 	"go/types"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -30,18 +31,28 @@ type sampleDecl struct {
 
 type dummyImporter struct{}
 
-func (_ dummyImporter) Import(path string) (*types.Package, error) {
+// Import imports unsafe or package from single file in tests/imports.
+func (di dummyImporter) Import(path string) (*types.Package, error) {
 	if path == "unsafe" {
 		return types.Unsafe, nil
 	}
-	return nil, errors.New("dummyImporter can import only unsafe package")
+	fname := filepath.Join("tests", "imports", path+".go")
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, fname, nil, 0)
+	if err != nil {
+		return nil, errors.New(
+			"dummyImporter can import only unsafe or tests/imports package",
+		)
+	}
+	cfg := types.Config{Importer: di}
+	return cfg.Check(path, fset, []*ast.File{f}, nil)
 }
 
 func (s sampleDecl) testDecl() error {
 	src := "package foo\n" + s.goDecl
 
 	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, s.filePos, src, 0)
+	f, err := parser.ParseFile(fset, s.filePos, src, parser.ParseComments)
 	if err != nil {
 		return err
 	}
@@ -59,6 +70,7 @@ func (s sampleDecl) testDecl() error {
 	}
 
 	gtc := gotoc.NewGTC(fset, pkg, ti, &gotoc.StdSizes{4, 8})
+	gtc.AddComments(f)
 	var cdds []*gotoc.CDD
 	for _, d := range f.Decls {
 		for _, cdd := range gtc.Decl(d, 0) {
@@ -246,3 +258,39 @@ func testDeclFile(fname string) error {
 	}
 	return nil
 }
+
+// rejected contains Go declarations that gotoc can not translate and msg that
+// must be printed in such case. gotoc exits after printing the message, so
+// every sample is translated by a separate process.
+var rejected = []struct {
+	goDecl, msg string
+}{
+	{
+		"func F(m map[string]int) int {\n\treturn m[\"a\"]\n}\n",
+		"not implemented: index of map[string]int",
+	},
+	{
+		"func F(m map[int]byte) {\n\tm[1] = 2\n}\n",
+		"not implemented: index of map[int]byte",
+	},
+}
+
+func TestRejected(t *testing.T) {
+	if s := os.Getenv("GOTOC_REJECTED"); s != "" {
+		n, _ := strconv.Atoi(s)
+		sd := sampleDecl{filePos: "rejected.go", goDecl: rejected[n].goDecl}
+		fmt.Fprintln(os.Stderr, sd.testDecl())
+		os.Exit(0)
+	}
+	for i, r := range rejected {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRejected$")
+		cmd.Env = append(os.Environ(), "GOTOC_REJECTED="+strconv.Itoa(i))
+		out, err := cmd.CombinedOutput()
+		if err == nil || !bytes.Contains(out, []byte(r.msg)) {
+			t.Errorf(
+				"not rejected:\n%s// want: %s\n// output:\n%s",
+				r.goDecl, r.msg, out,
+			)
+		}
+	}
+}
@@ -33,6 +33,7 @@ type GTC struct {
 	// TODO: safe concurent acces is need
 	tuples  map[string]types.Object
 	arrays  map[string]types.Object
+	structs map[string]types.Object
 	itables map[string]types.Object
 	tinfos  map[string]types.Object
 	minfos  map[string]types.Object
@@ -57,6 +58,7 @@ func NewGTC(fset *token.FileSet, pkg *types.Package, ti *types.Info, siz types.S
 		nextInt:     c,
 		tuples:      make(map[string]types.Object),
 		arrays:      make(map[string]types.Object),
+		structs:     make(map[string]types.Object),
 		itables:     make(map[string]types.Object),
 		tinfos:      make(map[string]types.Object),
 		minfos:      make(map[string]types.Object),
@@ -149,6 +151,14 @@ func (gtc *GTC) makeDefs(node ast.Node) bool {
 
 type imports map[*types.Package]bool
 
+// AddComments makes comments from f available for pragma lookup. Translate
+// calls it for all translated files.
+func (gtc *GTC) AddComments(f *ast.File) {
+	for k, v := range ast.NewCommentMap(gtc.fset, f, f.Comments) {
+		gtc.cmap[k] = v
+	}
+}
+
 // Translate translates files to C source.
 // It writes results of translation to:
 //	wh - C header, contains exported and inlined declarations translated to C,
@@ -157,9 +167,7 @@ func (gtc *GTC) Translate(wh, wc io.Writer, files []*ast.File) error {
 	var cdds []*CDD
 
 	for _, f := range files {
-		for k, v := range ast.NewCommentMap(gtc.fset, f, f.Comments) {
-			gtc.cmap[k] = v
-		}
+		gtc.AddComments(f)
 		ast.Inspect(f, gtc.makeDefs)
 	}
 	for _, f := range files {
@@ -452,7 +460,7 @@ func (gtc *GTC) exit(pos token.Pos, f string, a ...interface{}) {
 }
 
 func (gtc *GTC) notImplemented(n ast.Node, tl ...types.Type) {
-	fmt.Fprint(os.Stderr, gtc.fset.Position(n.Pos()))
+	fmt.Fprint(os.Stderr, gtc.fset.Position(n.Pos()), " ")
 	fmt.Fprintf(os.Stderr, "not implemented: %T\n", n)
 	for _, t := range tl {
 		fmt.Fprintf(os.Stderr, "	in case of: %T\n", t)
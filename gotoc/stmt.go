@@ -3,12 +3,18 @@ package gotoc
 import (
 	"bytes"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"strconv"
+	"strings"
 )
 
 func (cdd *CDD) ReturnStmt(w *bytes.Buffer, s *ast.ReturnStmt, resultT string, tup *types.Tuple) (end bool) {
+	if cdd.dfr != nil {
+		cdd.deferReturn(w, s, resultT, tup)
+		return
+	}
 	switch len(s.Results) {
 	case 0:
 		if resultT == "void" {
@@ -117,13 +123,20 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 
 		rhsIsTuple := len(s.Lhs) > 1 && len(s.Rhs) == 1
 
+		if rhsIsTuple && allBlank(s.Lhs) {
+			// Don't declare temporary variable that would be unused.
+			w.WriteString("(void)(")
+			cdd.Expr(w, s.Rhs[0], nil, true)
+			w.WriteString(");\n")
+			break
+		}
+
 		if rhsIsTuple {
 			tex := s.Rhs[0]
 			tup := cdd.exprType(tex).(*types.Tuple)
 			tupName := "_tmp" + cdd.gtc.uniqueId()
 			cdd.varDecl(w, tup, tupName, tex, "", false, true)
 			w.WriteByte('\n')
-			cdd.indent(w)
 			for i, n := 0, tup.Len(); i < n; i++ {
 				es := tupName + "._" + strconv.Itoa(i)
 				ltyp := cdd.exprType(s.Lhs[i])
@@ -188,7 +201,9 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 			}
 		}
 
-		if len(s.Rhs) == len(s.Lhs) && len(s.Lhs) > 1 && s.Tok != token.DEFINE {
+		parallel := len(s.Rhs) == len(s.Lhs) && len(s.Lhs) > 1 &&
+			s.Tok != token.DEFINE
+		if parallel {
 			for i, t := range typ {
 				if i > 0 {
 					cdd.indent(w)
@@ -205,7 +220,6 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 					rhs[i] = tmp
 				}
 			}
-			cdd.indent(w)
 		}
 
 		var atok string
@@ -217,14 +231,23 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 			atok = " &= "
 			rhs[0] = "~(" + rhs[0] + ")"
 
+		case token.SHL_ASSIGN, token.SHR_ASSIGN:
+			atok = " " + s.Tok.String() + " "
+			bop, n := cdd.bigShift(s.Tok, s.Rhs[0], cdd.exprType(s.Lhs[0]))
+			if bop != "" {
+				atok = " " + bop + "= "
+				rhs[0] = strconv.FormatUint(n, 10)
+			}
+
 		default:
 			atok = " " + s.Tok.String() + " "
 		}
-		indent := false
+		// Tuple and parallel assignment: first line has already been written.
+		indent := rhsIsTuple || parallel
 		for i := 0; i < len(lhs); i++ {
 			li := lhs[i]
-			if li == "_" && rhsIsTuple {
-				continue
+			if li == "_" && (rhsIsTuple || parallel) {
+				continue // Already evaluated.
 			}
 			if indent {
 				cdd.indent(w)
@@ -256,15 +279,25 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 			cdd.indent(w)
 		}
 
-		w.WriteString("if (")
-		cdd.Expr(w, s.Cond, nil, true)
-		w.WriteString(") ")
-		updateEnd(cdd.BlockStmt(w, s.Body, resultT, tup))
-		if s.Else == nil {
-			w.WriteByte('\n')
+		if cv := cdd.exprValue(s.Cond); cv != nil {
+			// Constant condition: translate only the branch that can run.
+			if constant.BoolVal(cv) {
+				updateEnd(cdd.BlockStmt(w, s.Body, resultT, tup))
+				w.WriteByte('\n')
+			} else if s.Else != nil {
+				updateEnd(cdd.Stmt(w, s.Else, "", resultT, tup))
+			}
 		} else {
-			w.WriteString(" else ")
-			updateEnd(cdd.Stmt(w, s.Else, "", resultT, tup))
+			w.WriteString("if (")
+			cdd.Expr(w, s.Cond, nil, true)
+			w.WriteString(") ")
+			updateEnd(cdd.BlockStmt(w, s.Body, resultT, tup))
+			if s.Else == nil {
+				w.WriteByte('\n')
+			} else {
+				w.WriteString(" else ")
+				updateEnd(cdd.Stmt(w, s.Else, "", resultT, tup))
+			}
 		}
 
 		if s.Init != nil {
@@ -534,7 +567,8 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 					if i != 0 {
 						w.WriteString(" || ")
 					}
-					cdd.eq(w, "_tag", "==", cdd.ExprStr(e, typ, true), typ, typ)
+					c, ctyp := cdd.eqOperand(e, cdd.exprType(e), typ, true)
+					cdd.eq(w, "_tag", "==", c, typ, ctyp)
 				}
 				w.WriteString(") ")
 			}
@@ -708,6 +742,9 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 		cdd.Complexity++
 		cdd.GoStmt(w, s)
 
+	case *ast.DeferStmt:
+		cdd.exit(s.Pos(), "not supported: defer outside function top-level block")
+
 	case *ast.SendStmt:
 		et := cdd.exprType(s.Chan).(*types.Chan).Elem()
 		val := cdd.interfaceExprStr(s.Value, et, true)
@@ -757,7 +794,7 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 				w.WriteString(", ")
 				et := cdd.exprType(s.Chan).(*types.Chan).Elem()
 				dim := cdd.Type(w, et)
-				dimFuncPtr("", dim)
+				w.WriteString(dimFuncPtr("", dim))
 				w.WriteString(", ")
 				cdd.interfaceExpr(w, s.Value, et, true)
 				w.WriteString(");\n")
@@ -765,10 +802,16 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 			default:
 				cdd.indent(w)
 				w.WriteString("RECVINIT(" + strconv.Itoa(i) + ", ")
-				var c ast.Expr
+				var (
+					c   ast.Expr
+					tup *types.Tuple
+				)
 				switch r := s.(type) {
 				case *ast.AssignStmt:
 					c = r.Rhs[0].(*ast.UnaryExpr).X
+					if len(r.Lhs) == 2 {
+						tup = cdd.exprType(r.Rhs[0]).(*types.Tuple)
+					}
 				case *ast.ExprStmt:
 					c = r.X.(*ast.UnaryExpr).X
 				default:
@@ -776,9 +819,15 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 				}
 				cdd.Expr(w, c, nil, true)
 				w.WriteString(", ")
-				et := cdd.exprType(c).(*types.Chan).Elem()
-				dim := cdd.Type(w, et)
-				dimFuncPtr("", dim)
+				if tup != nil {
+					// SELRECVOK receives into _0 field of tuple, like RECVOK.
+					tn, _ := cdd.tupleName(tup)
+					w.WriteString(tn)
+				} else {
+					et := cdd.exprType(c).(*types.Chan).Elem()
+					dim := cdd.Type(w, et)
+					w.WriteString(dimFuncPtr("", dim))
+				}
 				w.WriteString(");\n")
 			}
 		}
@@ -901,12 +950,26 @@ func (cdd *CDD) Stmt(w *bytes.Buffer, stmt ast.Stmt, label, resultT string, tup
 		cdd.indent(w)
 		w.WriteString("}}\n")
 
+		if label != "" {
+			cdd.label(w, label, "_break")
+		}
+
 	default:
 		cdd.notImplemented(s)
 	}
 	return
 }
 
+// allBlank reports whether all expressions in list are blank identifiers.
+func allBlank(list []ast.Expr) bool {
+	for _, e := range list {
+		if id, ok := e.(*ast.Ident); !ok || id.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
+
 type arg struct {
 	t types.Type
 	l string
@@ -921,10 +984,10 @@ type call struct {
 	args []arg
 }
 
-func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
+func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool, pfx string) *call {
 	c := new(call)
 	n := len(e.Args) + 1 // +1 for variadic function without any parameter.
-	fs, ft, rs, rt := cdd.funStr(e.Fun, e.Args)
+	fs, ft, rs, rt := cdd.funStr(e)
 	if t != nil {
 		ft = t
 	}
@@ -951,15 +1014,15 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 			c.fun.l = cast + "(" + rs + ".itab))->" + fs
 			c.args[n] = arg{types.Typ[types.Uintptr], "&" + rs + ".val", ""}
 		} else {
-			c.rcv = arg{rt, "_r", rs}
-			c.fun.l = cast + "_r.itab)->" + fs
-			c.args[n] = arg{types.Typ[types.Uintptr], "&_r" + ".val", ""}
+			c.rcv = arg{rt, pfx + "_r", rs}
+			c.fun.l = cast + pfx + "_r.itab)->" + fs
+			c.args[n] = arg{types.Typ[types.Uintptr], "&" + pfx + "_r.val", ""}
 		}
 		n++
 	} else if rs == "" {
 		if eval {
 			// Call of function or function variable.
-			c.fun = arg{ft, "_f", fs}
+			c.fun = arg{ft, pfx + "_f", fs}
 			if fident, ok := e.Fun.(*ast.Ident); ok {
 				if _, ok = cdd.object(fident).(*types.Var); !ok {
 					// Ordinary function call
@@ -972,8 +1035,8 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 	} else {
 		// Method call.
 		if eval {
-			c.rcv = arg{rt, "_r", rs}
-			c.args[n] = arg{rt, "_r", ""}
+			c.rcv = arg{rt, pfx + "_r", rs}
+			c.args[n] = arg{rt, pfx + "_r", ""}
 		} else {
 			c.args[n].l = rs
 		}
@@ -987,15 +1050,15 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 		a0 := e.Args[0]
 		if atup, _ := cdd.exprType(a0).(*types.Tuple); atup != nil {
 			c.tup.t = atup
-			c.tup.l = "_tup"
+			c.tup.l = pfx + "_tup"
 			c.tup.r = cdd.ExprStr(a0, c.tup.t, true)
 			for i := 0; i < alen; i++ {
 				it := tup.At(i).Type()
 				et := atup.At(i).Type()
-				ai := "_tup._" + strconv.Itoa(i)
+				ai := pfx + "_tup._" + strconv.Itoa(i)
 				s := cdd.interfaceESstr(nil, ai, a0.Pos(), et, it, true)
 				if eval || c.arr.t != nil {
-					c.args[n] = arg{it, "_" + strconv.Itoa(i), s}
+					c.args[n] = arg{it, pfx + "_" + strconv.Itoa(i), s}
 				} else {
 					c.args[n].l = s
 				}
@@ -1008,7 +1071,7 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 	variadic := sig.Variadic() && !e.Ellipsis.IsValid()
 	if variadic {
 		c.arr.t = tup.At(alen - 1).Type().(*types.Slice).Elem()
-		c.arr.l = "_a[]"
+		c.arr.l = pfx + "_a[]"
 	}
 	for i, a := range e.Args {
 		if a == nil {
@@ -1031,7 +1094,7 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 		}
 		s := cdd.interfaceExprStr(a, at, true)
 		if eval || c.arr.t != nil {
-			c.args[n] = arg{at, "_" + strconv.Itoa(i), s}
+			c.args[n] = arg{at, pfx + "_" + strconv.Itoa(i), s}
 		} else {
 			c.args[n].l = s
 		}
@@ -1051,7 +1114,7 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 				}
 			}
 		} else {
-			c.args[n].l = "CSLICE(" + strconv.Itoa(len(e.Args)-alen+1) + ", _a)"
+			c.args[n].l = "CSLICE(" + strconv.Itoa(len(e.Args)-alen+1) + ", " + pfx + "_a)"
 			c.arr.r = "{" + c.arr.r + "}"
 		}
 		n++
@@ -1060,8 +1123,37 @@ func (cdd *CDD) call(e *ast.CallExpr, t *types.Signature, eval bool) *call {
 	return c
 }
 
+// evalArgs evaluates receiver, function and arguments of c (argv) and saves
+// them in temporary variables.
+func (cdd *CDD) evalArgs(w *bytes.Buffer, c *call, argv []arg) {
+	if c.tup.t != nil {
+		cdd.indent(w)
+		cdd.Type(w, c.tup.t)
+		w.WriteString(" " + c.tup.l + " = " + indent(1, c.tup.r) + ";\n")
+	}
+	if c.rcv.r != "" {
+		argv = append([]arg{c.rcv}, argv...)
+	}
+	for i, arg := range argv {
+		if i == len(argv)-1 && c.arr.t != nil {
+			// Variadic function.
+			cdd.indent(w)
+			dim := cdd.Type(w, c.arr.t)
+			w.WriteString(" " + dimFuncPtr(c.arr.l, dim) + " = ")
+			w.WriteString(indent(1, c.arr.r) + ";\n")
+		}
+		if arg.r == "" {
+			continue // Don't evaluate
+		}
+		cdd.indent(w)
+		dim := cdd.Type(w, arg.t)
+		w.WriteString(" " + dimFuncPtr(arg.l, dim) + " = ")
+		w.WriteString(indent(1, arg.r) + ";\n")
+	}
+}
+
 func (cdd *CDD) GoStmt(w *bytes.Buffer, s *ast.GoStmt) {
-	c := cdd.call(s.Call, nil, true)
+	c := cdd.call(s.Call, nil, true, "")
 
 	if c.fun.r == "" && len(c.args) == 0 {
 		// Fast path: ordinary function without parameters.
@@ -1112,33 +1204,7 @@ func (cdd *CDD) GoStmt(w *bytes.Buffer, s *ast.GoStmt) {
 	cdd.il--
 	cdd.indent(w)
 	w.WriteString("}\n")
-	if c.tup.t != nil {
-		cdd.indent(w)
-		cdd.Type(w, c.tup.t)
-		w.WriteString(" " + c.tup.l + " = " + indent(1, c.tup.r) + ";\n")
-	}
-	if c.rcv.r != "" {
-		argv = append([]arg{c.rcv}, argv...)
-	}
-	for i, arg := range argv {
-		if i == len(argv)-1 && c.arr.t != nil {
-			// Variadic function.
-			cdd.indent(w)
-			dim := cdd.Type(w, c.arr.t)
-			w.WriteString(" " + dimFuncPtr(c.arr.l, dim) + " = ")
-			w.WriteString(indent(1, c.arr.r) + ";\n")
-		}
-		if arg.r == "" {
-			continue // Don't evaluate
-		}
-		cdd.indent(w)
-		dim := cdd.Type(w, arg.t)
-		w.WriteString(" " + dimFuncPtr(arg.l, dim) + " = ")
-		w.WriteString(indent(1, arg.r) + ";\n")
-	}
-	if c.rcv.r != "" {
-		argv = argv[1:]
-	}
+	cdd.evalArgs(w, c, argv)
 	cdd.indent(w)
 	w.WriteString("GO(wrap(")
 	comma = false
@@ -1166,6 +1232,150 @@ func (cdd *CDD) GoStmt(w *bytes.Buffer, s *ast.GoStmt) {
 	return
 }
 
+// hasDefer reports whether function body contains defer statement at top
+// level.
+func hasDefer(body *ast.BlockStmt) bool {
+	for _, s := range body.List {
+		if _, ok := s.(*ast.DeferStmt); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// deferBlock works like BlockStmt for function body that contains defer
+// statements. Deferred calls are translated to nested functions that are
+// called in reverse order in the function epilogue. Any return statement
+// saves results and jumps to the epilogue, so deferred closures can read and
+// modify named results. Deferred calls aren't run by panic.
+func (cdd *CDD) deferBlock(w *bytes.Buffer, bs *ast.BlockStmt, res *results, tup *types.Tuple) {
+	cdd.dfr = res
+	w.WriteString("{\n")
+	cdd.il++
+	cdd.indent(w)
+	w.WriteString("int_ _dn = 0;\n")
+	if !res.hasNames && res.typ != "void" {
+		cdd.indent(w)
+		w.WriteString(res.typ + " " + dimFuncPtr("_ret", res.dim) + ";\n")
+	}
+	n := 0
+	for _, s := range bs.List {
+		if ds, ok := s.(*ast.DeferStmt); ok {
+			n++
+			cdd.DeferStmt(w, ds, n)
+			continue
+		}
+		m := w.Len()
+		cdd.indent(w)
+		l := w.Len()
+		cdd.Stmt(w, s, "", res.typ, tup)
+		if w.Len() == l {
+			w.Truncate(m)
+		}
+	}
+	cdd.il--
+	cdd.indent(w)
+	w.WriteString("end:\n")
+	cdd.il++
+	cdd.indent(w)
+	w.WriteString("switch (_dn) {\n")
+	for i := n; i > 0; i-- {
+		cdd.indent(w)
+		w.WriteString("case " + strconv.Itoa(i) + ":\n")
+		cdd.il++
+		cdd.indent(w)
+		w.WriteString("_dfr" + strconv.Itoa(i) + "();\n")
+		cdd.il--
+	}
+	cdd.indent(w)
+	w.WriteString("}\n")
+	cdd.indent(w)
+	switch {
+	case res.typ == "void":
+		w.WriteString("return;\n")
+	case res.hasNames:
+		w.WriteString("return " + res.namedStr() + ";\n")
+	default:
+		w.WriteString("return _ret;\n")
+	}
+	cdd.il--
+	cdd.indent(w)
+	w.WriteByte('}')
+	cdd.dfr = nil
+}
+
+// DeferStmt translates k-th defer statement of function top-level block.
+// Function value, receiver and arguments are evaluated immediately and the
+// call is wrapped in nested function called by deferBlock epilogue.
+func (cdd *CDD) DeferStmt(w *bytes.Buffer, s *ast.DeferStmt, k int) {
+	if _, ok := cdd.exprType(s.Call.Fun).(*types.Signature); !ok {
+		cdd.exit(s.Pos(), "not supported: defer of builtin function")
+	}
+	ks := strconv.Itoa(k)
+	c := cdd.call(s.Call, nil, true, "_d"+ks)
+	argv := c.args
+	if c.fun.r != "" {
+		argv = append([]arg{c.fun}, c.args...)
+	}
+	cdd.evalArgs(w, c, argv)
+	cdd.indent(w)
+	w.WriteString("void _dfr" + ks + "() {\n")
+	cdd.il++
+	cdd.indent(w)
+	w.WriteString(c.fun.l + "(")
+	for i, arg := range c.args {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(arg.l)
+	}
+	w.WriteString(");\n")
+	cdd.il--
+	cdd.indent(w)
+	w.WriteString("}\n")
+	cdd.indent(w)
+	w.WriteString("_dn = " + ks + ";\n")
+}
+
+// deferReturn translates return statement of function that contains defer.
+func (cdd *CDD) deferReturn(w *bytes.Buffer, s *ast.ReturnStmt, resultT string, tup *types.Tuple) {
+	res := cdd.dfr
+	if len(s.Results) != 0 {
+		multi := res.hasNames && len(res.fields) > 1
+		if multi {
+			w.WriteString("{\n")
+			cdd.il++
+			cdd.indent(w)
+		}
+		// Use ordinary return statement to obtain result expression.
+		buf := new(bytes.Buffer)
+		cdd.dfr = nil
+		cdd.ReturnStmt(buf, s, resultT, tup)
+		cdd.dfr = res
+		val := strings.TrimSuffix(strings.TrimPrefix(buf.String(), "return "), ";\n")
+		switch {
+		case !res.hasNames:
+			w.WriteString("_ret = " + val + ";\n")
+		case !multi:
+			w.WriteString(res.names[0] + " = " + val + ";\n")
+		default:
+			w.WriteString(resultT + " _res = " + val + ";\n")
+			for i, name := range res.names {
+				if name == "_" {
+					continue
+				}
+				cdd.indent(w)
+				w.WriteString(name + " = _res." + res.fields[i].Name() + ";\n")
+			}
+			cdd.il--
+			cdd.indent(w)
+			w.WriteString("}\n")
+		}
+		cdd.indent(w)
+	}
+	w.WriteString("goto end;\n")
+}
+
 func (cdd *CDD) BlockStmt(w *bytes.Buffer, bs *ast.BlockStmt, resultT string, tup *types.Tuple) (end bool) {
 	w.WriteString("{\n")
 	cdd.il++
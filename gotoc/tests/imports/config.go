@@ -0,0 +1,12 @@
+package config
+
+const (
+	HSE     = 8  // MHz
+	SysClk  = 72 // MHz
+	PLLMul  = SysClk / HSE
+	APB1Div = 2
+
+	Tick = 1e6 / 1.5 // Hz
+)
+
+func SetupPLL(osc, div, mul int) {}
@@ -0,0 +1,9 @@
+package math
+
+const Pi = 3.14159265358979323846264338327950288419716939937510582097494459
+
+func Abs(x float64) float64    { return x }
+func Sqrt(x float64) float64   { return x }
+func Sqrt32(x float32) float32 { return x }
+func Sin(x float64) float64    { return x }
+func Cos(x float64) float64    { return x }
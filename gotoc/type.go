@@ -69,28 +69,7 @@ writeType:
 			w.WriteString("structE")
 			break
 		}
-		w.WriteString("struct {\n")
-		cdd.il++
-		for i, n := 0, t.NumFields(); i < n; i++ {
-			f := t.Field(i)
-			cdd.indent(w)
-			if tag := t.Tag(i); tag != "" {
-				//fmt.Println(cdd.gtc.fset.Position(f.Pos()), tag)
-				w.WriteString(reflect.StructTag(tag).Get("c"))
-				w.WriteByte(' ')
-			}
-			d := cdd.Type(w, f.Type())
-			w.WriteByte(' ')
-			name := dimFuncPtr(f.Name(), d)
-			if f.Name() == "_" {
-				name += strconv.Itoa(i) + "$"
-			}
-			w.WriteString(name + ";\n")
-		}
-
-		cdd.il--
-		cdd.indent(w)
-		w.WriteByte('}')
+		w.WriteString(cdd.structName(t))
 
 	case *types.Array:
 		w.WriteString(cdd.arrayName(t))
@@ -123,6 +102,34 @@ writeType:
 	return
 }
 
+func (cdd *CDD) structBody(w *bytes.Buffer, t *types.Struct) {
+	if t.NumFields() == 0 {
+		w.WriteString("structE")
+		return
+	}
+	w.WriteString("struct {\n")
+	cdd.il++
+	for i, n := 0, t.NumFields(); i < n; i++ {
+		f := t.Field(i)
+		cdd.indent(w)
+		if tag := t.Tag(i); tag != "" {
+			//fmt.Println(cdd.gtc.fset.Position(f.Pos()), tag)
+			w.WriteString(reflect.StructTag(tag).Get("c"))
+			w.WriteByte(' ')
+		}
+		d := cdd.Type(w, f.Type())
+		w.WriteByte(' ')
+		name := dimFuncPtr(f.Name(), d)
+		if f.Name() == "_" {
+			name += strconv.Itoa(i) + "$"
+		}
+		w.WriteString(name + ";\n")
+	}
+	cdd.il--
+	cdd.indent(w)
+	w.WriteByte('}')
+}
+
 func (cdd *CDD) iface(w *bytes.Buffer, it *types.Interface) {
 	w.WriteString("struct {\n")
 	cdd.il++
@@ -157,6 +164,37 @@ type results struct {
 	hasNames bool
 }
 
+// namedStr returns C expression with values of named results.
+func (res *results) namedStr() string {
+	if len(res.fields) == 1 {
+		return res.names[0]
+	}
+	all := true
+	for _, name := range res.names {
+		if name == "_" {
+			all = false
+			break
+		}
+	}
+	s := "(" + res.typ + "){"
+	comma := false
+	for i, name := range res.names {
+		if name == "_" {
+			continue
+		}
+		if comma {
+			s += ", "
+		} else {
+			comma = true
+		}
+		if !all {
+			s += "._" + strconv.Itoa(i) + "="
+		}
+		s += name
+	}
+	return s + "}"
+}
+
 func (cdd *CDD) results(tup *types.Tuple) (res results) {
 	if tup == nil {
 		res.typ = "void"
@@ -369,6 +407,31 @@ func (cdd *CDD) arrayName(a *types.Array) string {
 	return name
 }
 
+// structName returns the name of C type that corresponds to unnamed struct s.
+// Identical unnamed structs have the same C type, so they are assignable.
+func (cdd *CDD) structName(s *types.Struct) string {
+	name := "struct"
+	for i, n := 0, s.NumFields(); i < n; i++ {
+		f := s.Field(i)
+		typ, dim := cdd.TypeStr(f.Type())
+		name += "$$" + f.Name() + "$" + escape(dimFuncPtr(typ, dim))
+		if tag := reflect.StructTag(s.Tag(i)).Get("c"); tag != "" {
+			name += "$" + escape(tag)
+		}
+	}
+	if o, ok := cdd.gtc.structs[name]; ok {
+		cdd.addObject(o, true)
+		return name
+	}
+	o := types.NewTypeName(0, cdd.gtc.pkg, name, s)
+	cdd.gtc.structs[name] = o
+	cdd.addObject(o, true)
+	acd := cdd.gtc.newCDD(o, TypeDecl, 0)
+	cdd.acds = append(cdd.acds, acd)
+	acd.structDecl(new(bytes.Buffer), name, s, "")
+	return name
+}
+
 func (cdd *CDD) tiname(typ types.Type) string {
 	switch t := typ.(type) {
 	case *types.Pointer:
@@ -3,6 +3,7 @@ package main
 import (
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 func addtoreg(pkgs []*Package, bits *Bits) bool {
@@ -115,10 +116,12 @@ func bits(r *scanner, pkgs []*Package) {
 				bits = &Bits{Name: name, Mask: mp.mask, LSL: mp.pos, Descr: descr}
 			} else {
 				msk := strings.HasSuffix(name, "_Msk")
-				if msk {
-					if n := strings.Index(mask, "<<"); n > 0 {
-						mask = strings.TrimSpace(mask[:n])
+				var field string
+				if n := strings.Index(mask, "<<"); n > 0 {
+					if !msk {
+						field = strings.TrimSpace(mask[n+2:])
 					}
+					mask = strings.TrimSpace(mask[:n])
 				}
 				mask = strings.TrimSuffix(mask, "U")
 				m, err := strconv.ParseUint(mask, 0, 32)
@@ -127,6 +130,25 @@ func bits(r *scanner, pkgs []*Package) {
 					continue
 				}
 				switch {
+				case field != "":
+					key := strings.TrimSuffix(field, "_Pos")
+					mp, ok := maskPos[key]
+					if !ok || key == field {
+						warn("Bad bitmask", mask, "<<", field)
+						continue
+					}
+					if isHex(descr) {
+						// Single bit of multi-bit field (FIELD_0, FIELD_1).
+						continue
+					}
+					name = valueName(key, name, descr)
+					bits = &Bits{
+						Name: name, Mask: uint32(m), LSL: mp.pos, Descr: descr,
+					}
+					if !addtoreg(pkgs, bits) {
+						warn("Can not assign", name, "to any register.")
+					}
+					continue
 				case msk:
 					key := name[:len(name)-4]
 					mp := maskPos[key]
@@ -153,3 +175,45 @@ func bits(r *scanner, pkgs []*Package) {
 		}
 	}
 }
+
+func isHex(s string) bool {
+	if !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	_, err := strconv.ParseUint(s[2:], 16, 32)
+	return err == nil
+}
+
+// valueName returns name for value of multi-bit field. Newer CMSIS headers
+// define field values in the form:
+//
+//	#define PERIPH_REG_FIELD_n  (0xVU << PERIPH_REG_FIELD_Pos) /*!< Descr */
+//
+// If n is a decimal number (it is only an index of value, not its meaning) and
+// Descr is a single word (eg. Single, INCR4) valueName returns
+// PERIPH_REG_FIELD_DESCR. Otherwise it returns name unchanged.
+//
+// Fields which values aren't documented this way get no named values. Eg.
+// F303 header defines only OPAMP_CSR_VPSEL_0 and OPAMP_CSR_VPSEL_1 bits (with
+// hexadecimal description), and the meaning of VPSEL value (selected pin)
+// differs between OPAMP instances that share the CSR type, so it can not be
+// named by stm32xgen.
+func valueName(field, name, descr string) string {
+	if !strings.HasPrefix(name, field+"_") {
+		return name
+	}
+	for _, c := range name[len(field)+1:] {
+		if c < '0' || c > '9' {
+			return name
+		}
+	}
+	if descr == "" || !unicode.IsLetter(rune(descr[0])) {
+		return name
+	}
+	for _, c := range descr {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return name
+		}
+	}
+	return field + "_" + strings.ToUpper(descr)
+}
@@ -12,6 +12,23 @@ type IRQ struct {
 	Descr string
 }
 
+// IRQAlias describes an alternative name of interrupt defined in C header as:
+//
+//	#define Name_IRQn Target_IRQn
+type IRQAlias struct {
+	Name   string
+	Target string
+}
+
+func findIRQ(irqs []*IRQ, name string) *IRQ {
+	for _, irq := range irqs {
+		if irq.Name == name {
+			return irq
+		}
+	}
+	return nil
+}
+
 func interrupts(r *scanner) []*IRQ {
 	var irqs []*IRQ
 	for r.Scan() {
@@ -42,7 +59,21 @@ func interrupts(r *scanner) []*IRQ {
 			descr = strings.TrimSpace(strings.TrimSuffix(descr, "*/"))
 			line = strings.TrimSpace(line[:n])
 		}
-		num, err := strconv.ParseInt(strings.TrimSuffix(line, ","), 0, 0)
+		line = strings.TrimSuffix(line, ",")
+		if findIRQ(irqs, name) != nil {
+			warn("Duplicate IRQ name:", name)
+			continue
+		}
+		if strings.HasSuffix(line, "_IRQn") {
+			// Value is the name of previously defined interrupt.
+			irq := findIRQ(irqs, line[:len(line)-len("_IRQn")])
+			if irq == nil {
+				die("Unknown IRQ", line, "used as value of", name)
+			}
+			irqs = append(irqs, &IRQ{name, irq.Num, descr})
+			continue
+		}
+		num, err := strconv.ParseInt(line, 0, 0)
 		checkErr(err)
 		if num < -14 || num > 247 {
 			die("Bad IRQ number", num, "for", name, "interrupt.")
@@ -53,7 +84,44 @@ func interrupts(r *scanner) []*IRQ {
 	return irqs
 }
 
-func saveIRQs(irqs []*IRQ) {
+func irqAlias(line string) *IRQAlias {
+	def, line := split(removeComments(line))
+	if def != "#define" {
+		return nil
+	}
+	name, target := split(line)
+	if !strings.HasSuffix(name, "_IRQn") || !strings.HasSuffix(target, "_IRQn") {
+		return nil
+	}
+	return &IRQAlias{
+		Name:   name[:len(name)-len("_IRQn")],
+		Target: target[:len(target)-len("_IRQn")],
+	}
+}
+
+// resolveAlias follows chain of aliases and returns interrupt to which a
+// refers or nil if there is no such interrupt.
+func resolveAlias(irqs []*IRQ, aliases []*IRQAlias, a *IRQAlias) *IRQ {
+	for i := 0; i < len(aliases); i++ {
+		if irq := findIRQ(irqs, a.Target); irq != nil {
+			return irq
+		}
+		var next *IRQAlias
+		for _, b := range aliases {
+			if b.Name == a.Target {
+				next = b
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		a = next
+	}
+	return nil // Cycle.
+}
+
+func saveIRQs(irqs []*IRQ, aliases []*IRQAlias) {
 	mkdir("irq")
 	chdir("irq")
 	defer chdir("..")
@@ -72,5 +140,30 @@ func saveIRQs(irqs []*IRQ) {
 		}
 		fmt.Fprintf(w, "\t%s nvic.IRQ = %d // %s.\n", irq.Name, irq.Num, irq.Descr)
 	}
+	var saved []string
+	for _, a := range aliases {
+		if findIRQ(irqs, a.Name) != nil {
+			continue // Name is already used by interrupt.
+		}
+		irq := resolveAlias(irqs, aliases, a)
+		if irq == nil || irq.Num < 0 {
+			continue // Alias to interrupt that does not exist in this MCU.
+		}
+		dup := false
+		for _, s := range saved {
+			if s == a.Name {
+				dup = true
+				break
+			}
+		}
+		if dup {
+			continue
+		}
+		if len(saved) == 0 {
+			fmt.Fprintln(w, "\n\t// Aliases.")
+		}
+		saved = append(saved, a.Name)
+		fmt.Fprintf(w, "\t%s = %s\n", a.Name, irq.Name)
+	}
 	fmt.Fprintln(w, ")")
 }
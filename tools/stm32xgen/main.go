@@ -2,23 +2,43 @@
 //
 // stm32xgen is usually used this wahy:
 //  unifdef -k -f undef.h -D STM32TARGET stm32f4xx.h |stm32xgen PKGPATH
+//
+// If -ld FORMAT option is used stm32xgen additionally writes PKGPATH/memory.ld
+// file that describes FLASH and RAM regions in the form that can be included
+// in linker script. Supported formats:
+//  memory   MEMORY command,
+//  symbols  NAME_BASE, NAME_SIZE symbol assignments.
+// The length of every region must be known from the header or provided by
+// -size option, eg: -size CCMDATARAM=16K,SRAM=0x10000.
 package main
 
 import (
+	"flag"
 	"os"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		die("Usage: %s PKGPATH")
+	ldfmt := flag.String("ld", "", "write memory.ld in `FORMAT`: memory, symbols")
+	ldsize := flag.String(
+		"size", "", "`NAME=SIZE,...` lengths of memory regions for memory.ld",
+	)
+	flag.Parse()
+	if flag.NArg() != 1 {
+		die("Usage: stm32xgen [-ld FORMAT [-size NAME=SIZE,...]] PKGPATH")
 	}
-	pkgpath := os.Args[1]
+	switch *ldfmt {
+	case "", "memory", "symbols":
+	default:
+		die("Unknown linker script format:", *ldfmt)
+	}
+	pkgpath := flag.Arg(0)
 	checkErr(os.MkdirAll(pkgpath, 0755))
 	chdir(pkgpath)
 	var (
-		irqs []*IRQ
-		mmap []*MemGroup
-		pkgs []*Package
+		irqs    []*IRQ
+		aliases []*IRQAlias
+		mmap    []*MemGroup
+		pkgs    []*Package
 	)
 	r := newScanner(os.Stdin, "stdin")
 	for r.Scan() {
@@ -35,13 +55,19 @@ func main() {
 		case "Peripheral_Registers_Bits_Definition":
 			bits(r, pkgs)
 		default:
+			if a := irqAlias(r.Text()); a != nil {
+				aliases = append(aliases, a)
+			}
 			continue
 		}
 		goto noscan
 	}
 	checkErr(r.Err())
-	saveIRQs(irqs)
+	saveIRQs(irqs, aliases)
 	saveMmap(mmap)
+	if *ldfmt != "" {
+		saveLD(mmap, *ldfmt, *ldsize)
+	}
 	for _, pkg := range pkgs {
 		lastTweaks(pkg)
 		pkg.Save(pkgpath)
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 )
 
@@ -85,3 +86,236 @@ func saveMmap(mmap []*MemGroup) {
 		g.WriteTo(w)
 	}
 }
+
+// MemRegion describes FLASH or RAM region.
+type MemRegion struct {
+	Name   string
+	Origin uint64
+	Length uint64 // Zero means unknown length.
+}
+
+// memRegions returns memory regions described in the first (not peripheral)
+// group of mmap. Origins are calculated from the same base addresses that are
+// saved by saveMmap. Length is obtained from NAME_END, NAME_SIZE,
+// NAME_SIZE_MAX definitions or from the description, eg: SRAM1(128 KB).
+func memRegions(mmap []*MemGroup) []*MemRegion {
+	var (
+		group   *MemGroup
+		regions []*MemRegion
+	)
+	for _, g := range mmap {
+		if len(g.Bases) > 0 {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		return nil
+	}
+	vals := make(map[string]uint64)
+	for _, b := range group.Bases {
+		if v, ok := evalAddr(b.Addr, vals); ok {
+			vals[b.Name] = v
+		}
+	}
+	for _, b := range group.Bases {
+		name := strings.TrimSuffix(b.Name, "_BASE")
+		if name == b.Name || !isNumber(b.Addr) ||
+			!strings.Contains(name, "FLASH") && !strings.Contains(name, "RAM") ||
+			strings.HasSuffix(name, "_BB") {
+			continue
+		}
+		regions = append(regions, &MemRegion{
+			Name: name, Origin: vals[b.Name], Length: descrSize(b.Descr),
+		})
+	}
+	for _, b := range group.Bases {
+		v, ok := vals[b.Name]
+		if !ok {
+			continue
+		}
+		var (
+			reg *MemRegion
+			end bool
+		)
+		for _, r := range regions {
+			if reg != nil && len(reg.Name) >= len(r.Name) {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(b.Name, r.Name+"_") &&
+				strings.HasSuffix(b.Name, "_END"):
+				reg, end = r, true
+			case b.Name == r.Name+"_SIZE" || b.Name == r.Name+"_SIZE_MAX":
+				reg, end = r, false
+			}
+		}
+		switch {
+		case reg == nil:
+		case end:
+			reg.Length = v - reg.Origin + 1
+		default:
+			reg.Length = v
+		}
+	}
+	return regions
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseUint(s, 0, 64)
+	return err == nil
+}
+
+// evalAddr evaluates simple address expressions like: NAME + 0x0400.
+func evalAddr(addr string, vals map[string]uint64) (uint64, bool) {
+	var sum uint64
+	for _, s := range strings.Split(addr, "+") {
+		s = strings.TrimSpace(s)
+		if v, ok := vals[s]; ok {
+			sum += v
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSuffix(s, "U"), 0, 64)
+		if err != nil {
+			return 0, false
+		}
+		sum += v
+	}
+	return sum, true
+}
+
+// descrSize finds memory size in the description, eg: 16KB, (up to 1 MB).
+func descrSize(descr string) uint64 {
+	f := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(descr))
+	for i, s := range f {
+		unit := s
+		n := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		switch {
+		case n > 0:
+			s, unit = s[:n], s[n:]
+		case n < 0 && i+1 < len(f):
+			unit = f[i+1]
+		default:
+			continue
+		}
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch unit {
+		case "KB":
+			return v << 10
+		case "MB":
+			return v << 20
+		}
+	}
+	return 0
+}
+
+func ldSize(n uint64) string {
+	switch {
+	case n&(1<<20-1) == 0:
+		return strconv.FormatUint(n>>20, 10) + "M"
+	case n&(1<<10-1) == 0:
+		return strconv.FormatUint(n>>10, 10) + "K"
+	}
+	return fmt.Sprintf("0x%X", n)
+}
+
+// parseSize parses memory size in the form accepted by ldSize, eg: 16K, 1M,
+// 0x400, 4096.
+func parseSize(s string) (uint64, bool) {
+	shift := uint(0)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		shift = 10
+	case strings.HasSuffix(s, "M"):
+		shift = 20
+	}
+	if shift != 0 {
+		s = s[:len(s)-1]
+	}
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil || v == 0 {
+		return 0, false
+	}
+	return v << shift, true
+}
+
+// setSizes sets lengths of regions using sizes in the form: NAME=SIZE,...
+func setSizes(regions []*MemRegion, sizes string) {
+	if sizes == "" {
+		return
+	}
+	for _, ns := range strings.Split(sizes, ",") {
+		n := strings.IndexByte(ns, '=')
+		if n < 0 {
+			die("Bad memory region size:", ns)
+		}
+		name := ns[:n]
+		size, ok := parseSize(ns[n+1:])
+		if !ok {
+			die("Bad memory region size:", ns)
+		}
+		var reg *MemRegion
+		for _, r := range regions {
+			if r.Name == name {
+				reg = r
+				break
+			}
+		}
+		if reg == nil {
+			die("Unknown memory region:", name)
+		}
+		reg.Length = size
+	}
+}
+
+// saveLD writes memory.ld file. It exits with error if the length of some
+// region is unknown and is not provided in sizes (see setSizes).
+func saveLD(mmap []*MemGroup, format, sizes string) {
+	regions := memRegions(mmap)
+	setSizes(regions, sizes)
+	var unknown []string
+	for _, r := range regions {
+		if r.Length == 0 {
+			unknown = append(unknown, r.Name)
+		}
+	}
+	if len(unknown) > 0 {
+		die(
+			"Unknown length of memory region(s):", strings.Join(unknown, " "),
+			"(use -size NAME=SIZE,...)",
+		)
+	}
+	w := create("memory.ld")
+	defer w.Close()
+	fmt.Fprintln(w, "/* DO NOT EDIT THIS FILE. GENERATED BY stm32xgen. */")
+	fmt.Fprintln(w)
+	switch format {
+	case "memory":
+		n := 0
+		for _, r := range regions {
+			if len(r.Name) > n {
+				n = len(r.Name)
+			}
+		}
+		fmt.Fprintln(w, "MEMORY {")
+		for _, r := range regions {
+			attr := "(rwx)"
+			if strings.Contains(r.Name, "FLASH") {
+				attr = "(rx) "
+			}
+			fmt.Fprintf(
+				w, "\t%-*s %s : ORIGIN = 0x%08X, LENGTH = %s\n",
+				n, r.Name, attr, r.Origin, ldSize(r.Length),
+			)
+		}
+		fmt.Fprintln(w, "}")
+	case "symbols":
+		for _, r := range regions {
+			fmt.Fprintf(w, "%s_BASE = 0x%08X;\n", r.Name, r.Origin)
+			fmt.Fprintf(w, "%s_SIZE = %s;\n", r.Name, ldSize(r.Length))
+		}
+	}
+}
@@ -166,13 +166,32 @@ func peripherals(r *scanner) []*Package {
 		pbase  string
 		regs   []*Register
 		offset int
+		docoff int // Documented offset of the first register.
 	)
+	sizes := make(map[string]int) // Sizes of already defined *TypeDef.
 	for r.Scan() {
 		line := strings.TrimSpace(r.Text())
 		if bri := doxy(line, "@brief"); bri != "" {
 			brief = bri
 			continue
 		}
+		if f := strings.Fields(removeComments(line)); len(f) == 2 {
+			// Member of type defined before (eg. CAN_TxMailBox_TypeDef).
+			if size, ok := sizes[f[0]]; ok {
+				name := strings.TrimSuffix(f[1], ";")
+				if n := len(name) - 1; n >= 0 && name[n] == ']' {
+					m := strings.Index(name, "[")
+					if m < 0 {
+						r.Die("bad member name:", name)
+					}
+					n, err := strconv.ParseUint(name[m+1:n], 0, 32)
+					checkErr(err)
+					size *= int(n)
+				}
+				offset += size
+				continue
+			}
+		}
 		if uintx := strings.Index(line, "uint"); uintx >= 0 {
 			ioreg := strings.HasPrefix(line, "__I") // True for IO register.
 			line = line[uintx:]
@@ -216,6 +235,19 @@ func peripherals(r *scanner) []*Package {
 				offset += size
 				continue
 			}
+			// Registers of some sub-peripherals (eg. FMC banks, SAI blocks)
+			// are documented relative to the main peripheral, so only
+			// compare distances from the first register.
+			if o, ok := docOffset(line); ok {
+				if len(regs) == 0 {
+					docoff = o - offset
+				} else if o-docoff != offset {
+					r.Warn(
+						reg, "offset", fmt.Sprintf("0x%X", offset),
+						"differs from documented", fmt.Sprintf("0x%X", o-docoff),
+					)
+				}
+			}
 			var descr string
 			if n := strings.Index(line, "/*"); n > 0 {
 				descr = strings.TrimPrefix(line[n+2:], "!<")
@@ -256,6 +288,7 @@ func peripherals(r *scanner) []*Package {
 			} else {
 				r.Die("name of type (*TypeDef) expected after '}'")
 			}
+			sizes[line[:strings.Index(line, "TypeDef;")+7]] = offset
 			periph := line[:n]
 			pb := periph
 			if n := strings.IndexByte(pb, '_'); n > 0 {
@@ -293,6 +326,32 @@ func peripherals(r *scanner) []*Package {
 	return pkgs
 }
 
+// docOffset returns register offset documented in the comment in form:
+// Address offset: 0x1C (offsets are always hexadecimal).
+func docOffset(line string) (int, bool) {
+	n := strings.LastIndex(line, "ddress offset:")
+	if n < 0 {
+		return 0, false
+	}
+	line = strings.TrimSpace(line[n+14:])
+	if strings.HasPrefix(line, "0x") {
+		line = line[2:]
+	} else if line == "" || !unicode.IsDigit(rune(line[0])) {
+		return 0, false // Sometimes 0x is omitted: 400-7FF.
+	}
+	n = strings.IndexFunc(line, func(r rune) bool {
+		return !unicode.IsDigit(r) && !unicode.IsLetter(r)
+	})
+	if n >= 0 {
+		line = line[:n]
+	}
+	o, err := strconv.ParseUint(line, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int(o), true
+}
+
 func tweakPeriph(p *Periph) {
 	switch p.Name {
 	case "FSMC_Bank1", "FMC_Bank1":
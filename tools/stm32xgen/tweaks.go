@@ -40,6 +40,90 @@ func lastTweaks(pkg *Package) {
 			sdio(p)
 		}
 	}
+	dedup(pkg)
+}
+
+// dedup merges peripherals with identical register layout (names, sizes,
+// offsets) into the first of them, so one type is generated for all their
+// instances.
+func dedup(pkg *Package) {
+	periphs := pkg.Periphs[:0]
+loop:
+	for _, p := range pkg.Periphs {
+		for _, p1 := range periphs {
+			if sameRegs(p1.Regs, p.Regs) && !bitsConflict(p1.Regs, p.Regs) {
+				mergeBits(p1.Regs, p.Regs)
+				p1.Insts = append(p1.Insts, p.Insts...)
+				continue loop
+			}
+		}
+		periphs = append(periphs, p)
+	}
+	pkg.Periphs = periphs
+}
+
+func sameRegs(regs1, regs2 []*Register) bool {
+	if len(regs1) != len(regs2) {
+		return false
+	}
+	for i, r1 := range regs1 {
+		r2 := regs2[i]
+		if r1 == nil || r2 == nil {
+			if r1 != r2 {
+				return false
+			}
+			continue
+		}
+		if r1.Name != r2.Name || r1.Offset != r2.Offset ||
+			r1.BitSiz != r2.BitSiz || r1.Len != r2.Len ||
+			!sameRegs(r1.SubRegs, r2.SubRegs) {
+			return false
+		}
+	}
+	return true
+}
+
+// bitsConflict reports whether regs1 and regs2 define different bits with the
+// same name.
+func bitsConflict(regs1, regs2 []*Register) bool {
+	for i, r1 := range regs1 {
+		if r1 == nil {
+			continue
+		}
+		r2 := regs2[i]
+		for _, b2 := range r2.Bits {
+			for _, b1 := range r1.Bits {
+				if b1.Name == b2.Name &&
+					(b1.Mask != b2.Mask || b1.LSL != b2.LSL || b1.Val != b2.Val) {
+					return true
+				}
+			}
+		}
+		if bitsConflict(r1.SubRegs, r2.SubRegs) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBits adds bits from regs2 to regs1.
+func mergeBits(regs1, regs2 []*Register) {
+	for i, r1 := range regs1 {
+		if r1 == nil {
+			continue
+		}
+		r2 := regs2[i]
+	next:
+		for _, b2 := range r2.Bits {
+			for _, b1 := range r1.Bits {
+				if b1.Name == b2.Name {
+					continue next
+				}
+			}
+			r1.Bits = append(r1.Bits, b2)
+		}
+		mergeBits(r1.SubRegs, r2.SubRegs)
+	}
 }
 
 func fixbits(r *Register) {
@@ -100,7 +100,7 @@ func (w cwc) Close() error {
 		name = f.Name()
 	}
 	checkErr(w.c.Close())
-	if name != "" {
+	if strings.HasSuffix(name, ".go") {
 		name, err := filepath.Abs(name)
 		checkErr(err)
 		gofmt := exec.Command("gofmt", "-w", name)